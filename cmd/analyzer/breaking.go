@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"git-history-onboarding/internal/analysis/features"
+	"git-history-onboarding/internal/display"
+	"git-history-onboarding/internal/models"
+	"github.com/spf13/cobra"
+)
+
+func breaking(cmd *cobra.Command, args []string) {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+
+	repo, _, analyzer := setup(cmd)
+
+	commits, err := repo.GetCommitRange(from, to)
+	if err != nil {
+		log.Fatalf("Failed to list commits since %s: %v", from, err)
+	}
+	_, featureAnalysis, err := analyzeCommits(context.Background(), cmd, analyzer, commits)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Top-level features already include the breaking changes of their sub-features
+	var names []string
+	assigned := make(map[string]bool)
+	for name, feature := range featureAnalysis {
+		for _, change := range feature.BreakingChanges {
+			assigned[change.CommitHash] = true
+		}
+		if feature.Parent == "" && len(feature.BreakingChanges) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var other []models.BreakingChange
+	for _, commit := range commits {
+		if change, ok := features.ParseBreakingChange(commit); ok && !assigned[change.CommitHash] {
+			other = append(other, change)
+		}
+	}
+
+	if len(names) == 0 && len(other) == 0 {
+		fmt.Printf("\nNo breaking changes since %s\n", from)
+		return
+	}
+
+	fmt.Printf("\nBreaking changes since %s:\n", from)
+	for _, name := range names {
+		fmt.Printf("\n%s:\n", name)
+		printBreakingChanges(features.BreakingTimeline(featureAnalysis[name]))
+	}
+	if len(other) > 0 {
+		fmt.Println("\nOther:")
+		printBreakingChanges(features.BreakingTimeline(&models.Feature{BreakingChanges: other}))
+	}
+}
+
+func printBreakingChanges(changes []models.BreakingChange) {
+	for _, change := range changes {
+		fmt.Printf("  - [%s] %s (%s by %s)\n",
+			display.Date(change.Date),
+			change.Description,
+			display.ShortHash(change.CommitHash),
+			change.AuthorEmail)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"git-history-onboarding/internal/analysis/features"
+	"git-history-onboarding/internal/changelog"
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/issues"
+	"git-history-onboarding/internal/models"
+	"github.com/spf13/cobra"
+)
+
+func changelogCommand(cmd *cobra.Command, args []string) {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	version, _ := cmd.Flags().GetString("version")
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	prepend, _ := cmd.Flags().GetBool("prepend")
+
+	if format != "markdown" && format != "json" {
+		log.Fatalf("Unknown changelog format %q, expected markdown or json", format)
+	}
+	if prepend && (format != "markdown" || output == "") {
+		log.Fatal("--prepend needs --output and the markdown format")
+	}
+
+	repo, _, analyzer := setup(cmd)
+
+	commits, err := repo.GetCommitRange(from, to)
+	if err != nil {
+		log.Fatalf("Failed to list commits: %v", err)
+	}
+
+	if version == "" {
+		version = to
+	}
+	if version == "" {
+		version = changelog.Unreleased
+	}
+	var date time.Time
+	if version != changelog.Unreleased {
+		date = releaseDate(repo, to, commits)
+	}
+
+	_, featureAnalysis, err := analyzeCommits(context.Background(), cmd, analyzer, commits)
+	if err != nil {
+		log.Fatal(err)
+	}
+	featureOf := commitFeatures(featureAnalysis)
+	release := changelog.Build(version, date, commits, featureOf, analyzer.Issues)
+	links := changelogLinks(cmd)
+
+	var rendered string
+	switch {
+	case format == "json":
+		data, err := release.JSON()
+		if err != nil {
+			log.Fatalf("Failed to encode changelog: %v", err)
+		}
+		rendered = string(data) + "\n"
+	case prepend:
+		existing, err := os.ReadFile(output)
+		if err != nil && !os.IsNotExist(err) {
+			log.Fatalf("Failed to read %s: %v", output, err)
+		}
+		rendered = changelog.Prepend(string(existing), release, links)
+	default:
+		rendered = release.Markdown(links)
+	}
+
+	writeOutput(output, []byte(rendered))
+}
+
+// releaseDate is the date of the tag the release ends at, or of its newest commit when
+// --to is not a tag
+func releaseDate(repo *git.Repository, to string, commits []git.CommitInfo) time.Time {
+	tags, err := repo.Tags()
+	if err != nil {
+		log.Fatalf("Failed to list tags: %v", err)
+	}
+	for _, tag := range tags {
+		if tag.Name == to {
+			return tag.Date
+		}
+	}
+	if len(commits) > 0 {
+		return commits[0].Commit.Author.When
+	}
+	return time.Time{}
+}
+
+// commitFeatures picks one feature per commit: the most confident assignment, and the
+// most specific feature when sub-features share it with their parents
+func commitFeatures(featureAnalysis map[string]*models.Feature) map[string]string {
+	featureOf := make(map[string]string)
+	best := make(map[string]models.Assignment)
+	for name, feature := range featureAnalysis {
+		for hash, assignment := range feature.Assignments {
+			current, ok := featureOf[hash]
+			switch {
+			case !ok,
+				assignment.Confidence > best[hash].Confidence,
+				assignment.Confidence == best[hash].Confidence && moreSpecific(featureAnalysis, name, current):
+				featureOf[hash] = name
+				best[hash] = assignment
+			}
+		}
+	}
+	return featureOf
+}
+
+func moreSpecific(featureAnalysis map[string]*models.Feature, name, other string) bool {
+	depth, otherDepth := features.Depth(featureAnalysis, name), features.Depth(featureAnalysis, other)
+	if depth != otherDepth {
+		return depth > otherDepth
+	}
+	return name < other
+}
+
+// changelogLinks builds commit and issue URLs from the issue tracker flags
+func changelogLinks(cmd *cobra.Command) changelog.Links {
+	githubRepo, _ := cmd.Flags().GetString("github-repo")
+	githubURL, _ := cmd.Flags().GetString("github-url")
+	gitlabProject, _ := cmd.Flags().GetString("gitlab-project")
+	gitlabURL, _ := cmd.Flags().GetString("gitlab-url")
+	jiraURL, _ := cmd.Flags().GetString("jira-url")
+
+	links := changelog.Links{Issues: make(map[string]string)}
+	switch {
+	case githubRepo != "":
+		web := "https://github.com"
+		if githubURL != "" && !strings.Contains(githubURL, "api.github.com") {
+			// GitHub Enterprise serves its API under /api/v3
+			web = strings.TrimSuffix(strings.TrimSuffix(githubURL, "/"), "/api/v3")
+		}
+		base := web + "/" + githubRepo
+		links.Commit = base + "/commit/%s"
+		links.Issues[issues.TrackerGitHub] = base + "/issues/%s"
+	case gitlabProject != "":
+		web := "https://gitlab.com"
+		if gitlabURL != "" {
+			web = strings.TrimSuffix(gitlabURL, "/")
+		}
+		base := web + "/" + gitlabProject
+		links.Commit = base + "/-/commit/%s"
+		links.Issues[issues.TrackerGitLab] = base + "/-/issues/%s"
+	}
+	if jiraURL != "" {
+		links.Issues[issues.TrackerJira] = strings.TrimSuffix(jiraURL, "/") + "/browse/%s"
+	}
+	return links
+}
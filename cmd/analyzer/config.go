@@ -0,0 +1,25 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"git-history-onboarding/internal/models"
+)
+
+// loadConfig reads a JSON feature detection config; an empty path yields the defaults
+func loadConfig(path string) (models.FeatureDetectionConfig, error) {
+	var config models.FeatureDetectionConfig
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, err
+	}
+	return config, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"os"
+
+	"git-history-onboarding/internal/console"
+	"git-history-onboarding/internal/report"
+	"github.com/spf13/cobra"
+)
+
+func diffCommand(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	colorMode, _ := cmd.Flags().GetString("color")
+
+	if format != "text" && format != "json" {
+		log.Fatalf("Unknown format %q, expected text or json", format)
+	}
+	color, err := console.UseColor(colorMode, os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if output != "" && colorMode == "auto" {
+		color = false
+	}
+
+	older, err := report.Load(args[0])
+	if err != nil {
+		log.Fatalf("Failed to load %s: %v", args[0], err)
+	}
+	newer, err := report.Load(args[1])
+	if err != nil {
+		log.Fatalf("Failed to load %s: %v", args[1], err)
+	}
+	if older.Repository.URL != newer.Repository.URL {
+		log.Printf("Warning: comparing reports of different repositories, %s and %s", older.Repository.URL, newer.Repository.URL)
+	}
+
+	diff := report.Compare(older, newer, threshold)
+	if format == "json" {
+		data, err := diff.JSON()
+		if err != nil {
+			log.Fatalf("Failed to encode diff: %v", err)
+		}
+		writeOutput(output, data)
+		return
+	}
+	var buf bytes.Buffer
+	if err := console.WriteDiff(&buf, diff, color); err != nil {
+		log.Fatalf("Failed to write diff: %v", err)
+	}
+	writeOutput(output, buf.Bytes())
+}
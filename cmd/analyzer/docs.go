@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"git-history-onboarding/internal/docs"
+	"github.com/spf13/cobra"
+)
+
+func docsCommand(cmd *cobra.Command, args []string) {
+	output, _ := cmd.Flags().GetString("output")
+	dir, _ := cmd.Flags().GetString("dir")
+	templates, _ := cmd.Flags().GetString("templates")
+
+	renderer, err := docs.NewRenderer(templates)
+	if err != nil {
+		log.Fatalf("Failed to load templates: %v", err)
+	}
+
+	_, commits, analyzer := setup(cmd)
+	commits, featureAnalysis, err := analyzeCommits(context.Background(), cmd, analyzer, commits)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	repoURL, _ := cmd.Flags().GetString("repo")
+	guide := docs.Build(repoURL, commits, featureAnalysis, changelogLinks(cmd).Commit)
+
+	if dir != "" {
+		written, err := renderer.WritePages(guide, dir)
+		if err != nil {
+			log.Fatalf("Failed to write onboarding pages: %v", err)
+		}
+		fmt.Printf("Wrote %d pages to %s\n", written, dir)
+		return
+	}
+	if err := renderer.WriteGuide(guide, output); err != nil {
+		log.Fatalf("Failed to write onboarding guide: %v", err)
+	}
+	fmt.Printf("Wrote %s\n", output)
+}
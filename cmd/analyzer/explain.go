@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"git-history-onboarding/internal/display"
+	"git-history-onboarding/internal/git"
+	"github.com/spf13/cobra"
+)
+
+func explain(cmd *cobra.Command, args []string) {
+	_, commits, analyzer := setup(cmd)
+
+	commit, ok := findCommit(commits, args[0])
+	if !ok {
+		log.Fatalf("Commit not found: %s", args[0])
+	}
+
+	fmt.Printf("\nCommit: %s\n", commit.Commit.Hash)
+	fmt.Printf("Author: %s\n", commit.Commit.Author.Email)
+	fmt.Printf("Message: %s\n", display.FirstLine(commit.Commit.Message))
+
+	matches := analyzer.Explain(commit)
+	if len(matches) == 0 {
+		fmt.Println("\nNo feature matched this commit")
+		return
+	}
+
+	fmt.Println("\nFeatures:")
+	for _, match := range matches {
+		fmt.Printf("  - %s (confidence %.2f)\n", match.Feature, match.Confidence)
+		fmt.Printf("    %s detector matched %s %q against pattern %q\n",
+			match.Detector, match.Rule, match.Text, match.Pattern)
+	}
+}
+
+// findCommit looks a commit up by full or abbreviated hash
+func findCommit(commits []git.CommitInfo, hash string) (git.CommitInfo, bool) {
+	for _, commit := range commits {
+		if strings.HasPrefix(commit.Commit.Hash.String(), strings.ToLower(hash)) {
+			return commit, true
+		}
+	}
+	return git.CommitInfo{}, false
+}
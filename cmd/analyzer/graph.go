@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"git-history-onboarding/internal/analysis/features"
+	"git-history-onboarding/internal/graph"
+	"git-history-onboarding/internal/report"
+	"github.com/spf13/cobra"
+)
+
+func graphCommand(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	depth, _ := cmd.Flags().GetInt("depth")
+	minShare, _ := cmd.Flags().GetFloat64("min-share")
+	minCommits, _ := cmd.Flags().GetInt("min-commits")
+	maxFiles, _ := cmd.Flags().GetInt("max-files")
+	maxEdges, _ := cmd.Flags().GetInt("max-edges")
+	if format != "dot" && format != "mermaid" {
+		log.Fatalf("Unknown format %q, expected dot or mermaid", format)
+	}
+
+	_, commits, analyzer := setup(cmd)
+	// Co-change uses the commits without their excluded files, as the features do
+	commits, allFeatures, err := analyzeCommits(context.Background(), cmd, analyzer, commits)
+	if err != nil {
+		log.Fatal(err)
+	}
+	featureAnalysis := features.Collapse(allFeatures, depth)
+	repoURL, _ := cmd.Flags().GetString("repo")
+	rep := report.New(repoURL, commits, featureAnalysis)
+
+	var g graph.Graph
+	switch args[0] {
+	case "ownership":
+		g = graph.Ownership(rep, minShare)
+	case "coupling":
+		g = graph.Coupling(rep, minCommits)
+	case "cochange":
+		g = graph.CoChange(rep, minCommits, maxFiles)
+	}
+	g = g.Strongest(maxEdges)
+
+	if format == "mermaid" {
+		writeOutput(output, []byte(g.Mermaid()))
+		return
+	}
+	writeOutput(output, []byte(g.DOT()))
+}
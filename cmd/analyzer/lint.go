@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"git-history-onboarding/internal/analysis/features"
+	"github.com/spf13/cobra"
+)
+
+func lint(cmd *cobra.Command, args []string) {
+	_, commits, _ := setup(cmd)
+
+	results := features.LintCommits(commits)
+	total, nonConforming := 0, 0
+	for _, result := range results {
+		total += result.Commits
+		nonConforming += result.NonConforming
+	}
+	if total == 0 {
+		fmt.Println("\nNo commits to lint")
+		return
+	}
+
+	fmt.Printf("\nConventional Commits: %d of %d commits do not conform (%.1f%%)\n",
+		nonConforming, total, float64(nonConforming)/float64(total)*100)
+
+	fmt.Println("\nBy Author:")
+	for _, result := range results {
+		fmt.Printf("  - %s: %d of %d (%.1f%%)\n",
+			result.AuthorEmail, result.NonConforming, result.Commits, result.Share()*100)
+
+		problems := make([]string, 0, len(result.Problems))
+		for problem := range result.Problems {
+			problems = append(problems, problem)
+		}
+		sort.Slice(problems, func(i, j int) bool {
+			if result.Problems[problems[i]] != result.Problems[problems[j]] {
+				return result.Problems[problems[i]] > result.Problems[problems[j]]
+			}
+			return problems[i] < problems[j]
+		})
+		for _, problem := range problems {
+			fmt.Printf("      %s (%d)\n", problem, result.Problems[problem])
+		}
+	}
+}
@@ -1,14 +1,20 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/spf13/cobra"
-	"git-history-onboarding/internal/git"
 	"git-history-onboarding/internal/analysis/features"
+	"git-history-onboarding/internal/analysis/szz"
+	"git-history-onboarding/internal/console"
+	"git-history-onboarding/internal/pdf"
+	"git-history-onboarding/internal/report"
+	"git-history-onboarding/internal/site"
+	"git-history-onboarding/internal/sqlite"
 )
 
 func main() {
@@ -18,8 +24,136 @@ func main() {
 		Run:   analyze,
 	}
 
-	rootCmd.Flags().StringP("repo", "r", "", "Repository URL to analyze")
-	rootCmd.MarkFlagRequired("repo")
+	rootCmd.PersistentFlags().StringP("repo", "r", "", "Repository URL to analyze (required except for diff and serve --load)")
+	rootCmd.PersistentFlags().StringP("strategy", "s", "patterns", "Feature detector to use when the config names none: patterns, paths, codeowners, modules or directories")
+	rootCmd.PersistentFlags().StringP("config", "c", "", "JSON feature detection config file")
+	rootCmd.PersistentFlags().String("github-repo", "", "GitHub owner/name to fetch referenced issues from")
+	rootCmd.PersistentFlags().String("github-url", "", "GitHub API URL (defaults to https://api.github.com)")
+	rootCmd.PersistentFlags().String("gitlab-project", "", "GitLab project path or ID to fetch referenced issues from")
+	rootCmd.PersistentFlags().String("gitlab-url", "", "GitLab URL (defaults to https://gitlab.com)")
+	rootCmd.PersistentFlags().String("jira-url", "", "Jira URL to fetch referenced issues from")
+
+	addAnalyzeFlags(rootCmd)
+
+	analyzeCmd := &cobra.Command{
+		Use:   "analyze",
+		Short: "Print features with their owners and bug history",
+		Run:   analyze,
+	}
+	addAnalyzeFlags(analyzeCmd)
+	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "explain <commit>",
+		Short: "Explain which features a commit was assigned to and why",
+		Args:  cobra.ExactArgs(1),
+		Run:   explain,
+	})
+
+	breakingCmd := &cobra.Command{
+		Use:   "breaking",
+		Short: "List breaking changes since a release, grouped by feature",
+		Run:   breaking,
+	}
+	breakingCmd.Flags().String("from", "", "Tag or commit of the release to list breaking changes since")
+	breakingCmd.Flags().String("to", "", "Tag or commit to stop at (defaults to HEAD)")
+	breakingCmd.MarkFlagRequired("from")
+	rootCmd.AddCommand(breakingCmd)
+
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "lint",
+		Short: "Report commits that do not follow Conventional Commits, per author",
+		Run:   lint,
+	})
+
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "scopes",
+		Short: "List conventional commit scopes missing from ScopeAliases, by commit count",
+		Run:   scopes,
+	})
+
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "timeline",
+		Short: "Summarize every tagged release: commits, features, bug fixes, breaking changes and contributors",
+		Run:   timelineCommand,
+	})
+
+	storyCmd := &cobra.Command{
+		Use:   "story <feature>",
+		Short: "Tell the story of a feature as Markdown: its phases, who drove them and key commits",
+		Args:  cobra.ExactArgs(1),
+		Run:   story,
+	}
+	storyCmd.Flags().Int("gap-days", 30, "Days without commits that end a phase")
+	storyCmd.Flags().StringP("output", "o", "", "File to write instead of printing")
+	rootCmd.AddCommand(storyCmd)
+
+	docsCmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Write a Markdown onboarding guide with a page per feature",
+		Run:   docsCommand,
+	}
+	docsCmd.Flags().StringP("output", "o", "ONBOARDING.md", "Guide file to write")
+	docsCmd.Flags().String("dir", "", "Directory to write an index and one page per feature to, instead of a single guide")
+	docsCmd.Flags().String("templates", "", "Directory of *.tmpl templates overriding the defaults")
+	rootCmd.AddCommand(docsCmd)
+
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Write a static HTML site with features, ownership charts, activity and contributors",
+		Run:   reportCommand,
+	}
+	reportCmd.Flags().String("html", "", "Directory to write the HTML site to")
+	rootCmd.AddCommand(reportCmd)
+
+	graphCmd := &cobra.Command{
+		Use:       "graph <ownership|coupling|cochange>",
+		Short:     "Write people-to-feature ownership, feature coupling or file co-change graphs as DOT or Mermaid",
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{"ownership", "coupling", "cochange"},
+		Run:       graphCommand,
+	}
+	graphCmd.Flags().String("format", "dot", "Output format: dot or mermaid")
+	graphCmd.Flags().StringP("output", "o", "", "File to write instead of printing")
+	graphCmd.Flags().Int("depth", 0, "Feature hierarchy levels to include (0 includes all)")
+	graphCmd.Flags().Float64("min-share", 0.1, "Ownership: smallest share of a feature to draw")
+	graphCmd.Flags().Int("min-commits", 2, "Coupling and co-change: fewest shared commits to draw")
+	graphCmd.Flags().Int("max-files", 30, "Co-change: ignore commits changing more files (0 keeps all)")
+	graphCmd.Flags().Int("max-edges", 0, "Draw only the heaviest edges (0 draws all)")
+	rootCmd.AddCommand(graphCmd)
+
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a web UI and JSON API to browse features, owners, commits and files",
+		Run:   serve,
+	}
+	serveCmd.Flags().String("addr", "localhost:8080", "Address to listen on")
+	serveCmd.Flags().String("load", "", "Saved analyze --format json report to serve instead of analyzing --repo")
+	rootCmd.AddCommand(serveCmd)
+
+	diffCmd := &cobra.Command{
+		Use:   "diff <old.json> <new.json>",
+		Short: "Compare two saved analyses: features gained and lost, owner changes and new bugs",
+		Args:  cobra.ExactArgs(2),
+		Run:   diffCommand,
+	}
+	diffCmd.Flags().Float64("threshold", 0.1, "Smallest ownership share shift to report (0.1 is 10 percentage points)")
+	diffCmd.Flags().String("format", "text", "Output format: text or json")
+	diffCmd.Flags().StringP("output", "o", "", "File to write instead of printing")
+	diffCmd.Flags().String("color", "auto", "Color output: auto (on terminals), always or never")
+	rootCmd.AddCommand(diffCmd)
+
+	changelogCmd := &cobra.Command{
+		Use:   "changelog",
+		Short: "Generate a Keep a Changelog release from conventional commits",
+		Run:   changelogCommand,
+	}
+	changelogCmd.Flags().String("from", "", "Tag or commit of the previous release (defaults to the whole history)")
+	changelogCmd.Flags().String("to", "", "Tag or commit of the release (defaults to HEAD)")
+	changelogCmd.Flags().String("version", "", "Version heading (defaults to --to, or Unreleased)")
+	changelogCmd.Flags().String("format", "markdown", "Output format: markdown or json")
+	changelogCmd.Flags().StringP("output", "o", "", "File to write instead of printing")
+	changelogCmd.Flags().Bool("prepend", false, "Add the release to the top of the existing --output changelog, skipping listed commits")
+	rootCmd.AddCommand(changelogCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -27,54 +161,122 @@ func main() {
 	}
 }
 
+// addAnalyzeFlags adds the flags of analyze, which also runs without a subcommand
+func addAnalyzeFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("depth", 0, "Feature hierarchy levels to show (0 shows all)")
+	cmd.Flags().Bool("szz", false, "Trace bug fixes back to the commits that introduced them (slow)")
+	cmd.Flags().Bool("cancel-reverts", false, "Leave reverts and the commits they undid out of commits and ownership")
+	cmd.Flags().String("format", "text", "Output format: text, json, csv, pdf or sqlite")
+	cmd.Flags().StringP("output", "o", "", "File to write instead of printing, required for pdf and sqlite; the directory for csv tables")
+	cmd.Flags().StringSlice("tables", report.Tables, "CSV tables to write")
+	cmd.Flags().Bool("all", false, "Text: include features without commits")
+	cmd.Flags().String("color", "auto", "Text: color output: auto (on terminals), always or never")
+	cmd.Flags().String("save", "", "Also save the full analysis as a JSON snapshot, for diff")
+}
+
 func analyze(cmd *cobra.Command, args []string) {
-	repoURL, _ := cmd.Flags().GetString("repo")
-	
-	ctx := context.Background()
-	repo, err := git.Clone(ctx, repoURL)
+	depth, _ := cmd.Flags().GetInt("depth")
+	traceBugs, _ := cmd.Flags().GetBool("szz")
+	cancelReverts, _ := cmd.Flags().GetBool("cancel-reverts")
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+
+	tables, _ := cmd.Flags().GetStringSlice("tables")
+	all, _ := cmd.Flags().GetBool("all")
+	colorMode, _ := cmd.Flags().GetString("color")
+	save, _ := cmd.Flags().GetString("save")
+
+	switch format {
+	case "text", "json":
+	case "csv":
+		if err := report.CheckTables(tables); err != nil {
+			log.Fatal(err)
+		}
+		if output == "" && len(tables) != 1 {
+			log.Fatal("--format csv needs an --output directory, or a single table in --tables to print")
+		}
+	case "pdf", "sqlite":
+		if output == "" {
+			log.Fatalf("--format %s needs an --output file", format)
+		}
+	default:
+		log.Fatalf("Unknown format %q, expected text, json, csv, pdf or sqlite", format)
+	}
+	color, err := console.UseColor(colorMode, os.Stdout)
 	if err != nil {
-		log.Fatalf("Failed to clone repository: %v", err)
+		log.Fatal(err)
+	}
+	if output != "" && colorMode == "auto" {
+		color = false
 	}
 
-	commits, err := repo.GetCommitHistory()
+	repo, commits, analyzer := setup(cmd)
+	analyzer.CancelReverts = cancelReverts
+	commits, allFeatures, err := analyzeCommits(context.Background(), cmd, analyzer, commits)
 	if err != nil {
-		log.Fatalf("Failed to get commit history: %v", err)
-	}
-
-	fmt.Printf("Found %d commits\n", len(commits))
-	
-	// Analyze features
-	analyzer := features.NewAnalyzer()
-	featureAnalysis := analyzer.AnalyzeCommits(commits)
-
-	// Print feature analysis
-	fmt.Println("\nFeature Analysis:")
-	for name, feature := range featureAnalysis {
-		fmt.Printf("\nFeature: %s\n", name)
-		fmt.Printf("Created: %s\n", feature.CreatedAt.Format("2006-01-02"))
-		fmt.Printf("Last Updated: %s\n", feature.LastUpdated.Format("2006-01-02"))
-		
-		fmt.Println("Primary Owners:")
-		for email, percentage := range feature.Owners {
-			fmt.Printf("  - %s (%.1f%%)\n", email, percentage*100)
+		log.Fatal(err)
+	}
+	featureAnalysis := features.Collapse(allFeatures, depth)
+
+	if traceBugs {
+		if err := szz.NewAnalyzer(repo).TraceFeatures(featureAnalysis); err != nil {
+			log.Fatalf("Failed to trace bug-introducing commits: %v", err)
+		}
+	}
+
+	repoURL, _ := cmd.Flags().GetString("repo")
+	rep := report.New(repoURL, commits, featureAnalysis)
+	if save != "" {
+		data, err := rep.JSON()
+		if err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
 		}
-		
-		fmt.Println("Backup Owners:")
-		for email, percentage := range feature.BackupOwners {
-			fmt.Printf("  - %s (%.1f%%)\n", email, percentage*100)
+		if err := os.WriteFile(save, data, 0644); err != nil {
+			log.Fatalf("Failed to write %s: %v", save, err)
 		}
-		
-		fmt.Printf("Number of Commits: %d\n", len(feature.Commits))
-		fmt.Printf("Number of Bugs: %d\n", len(feature.Bugs))
-		
-		if len(feature.Bugs) > 0 {
-			fmt.Println("Bug History:")
-			for _, bug := range feature.Bugs {
-				fmt.Printf("  - [%s] by %s: %s\n", 
-					bug.FixedAt.Format("2006-01-02"),
-					bug.AuthorEmail,
-					bug.Description)
+		fmt.Fprintf(os.Stderr, "Saved %s\n", save)
+	}
+	switch format {
+	case "json":
+		data, err := rep.JSON()
+		if err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
+		}
+		writeOutput(output, data)
+		return
+	case "csv":
+		if output == "" {
+			if err := rep.WriteCSV(os.Stdout, tables[0]); err != nil {
+				log.Fatalf("Failed to write CSV: %v", err)
 			}
+			return
+		}
+		paths, err := rep.WriteCSVDir(output, tables)
+		if err != nil {
+			log.Fatalf("Failed to write CSV: %v", err)
 		}
+		for _, path := range paths {
+			fmt.Printf("Wrote %s\n", path)
+		}
+		return
+	case "pdf":
+		data, err := pdf.Render(site.Build(repoURL, commits, featureAnalysis, ""))
+		if err != nil {
+			log.Fatalf("Failed to render PDF: %v", err)
+		}
+		writeOutput(output, data)
+		return
+	case "sqlite":
+		if err := sqlite.WriteFile(output, sqlite.Export(repoURL, commits, featureAnalysis)); err != nil {
+			log.Fatalf("Failed to write SQLite database: %v", err)
+		}
+		fmt.Printf("Wrote %s\n", output)
+		return
 	}
-} 
\ No newline at end of file
+
+	var buf bytes.Buffer
+	if err := console.Write(&buf, featureAnalysis, console.Options{All: all, Color: color, BugTraces: traceBugs}); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+	writeOutput(output, buf.Bytes())
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"git-history-onboarding/internal/site"
+	"github.com/spf13/cobra"
+)
+
+func reportCommand(cmd *cobra.Command, args []string) {
+	htmlDir, _ := cmd.Flags().GetString("html")
+	if htmlDir == "" {
+		log.Fatal("Choose a report to write, e.g. --html out/")
+	}
+
+	_, commits, analyzer := setup(cmd)
+	commits, featureAnalysis, err := analyzeCommits(context.Background(), cmd, analyzer, commits)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	repoURL, _ := cmd.Flags().GetString("repo")
+	pages, err := site.Write(site.Build(repoURL, commits, featureAnalysis, changelogLinks(cmd).Commit), htmlDir)
+	if err != nil {
+		log.Fatalf("Failed to write HTML report: %v", err)
+	}
+	fmt.Printf("Wrote %d pages to %s\n", pages, htmlDir)
+}
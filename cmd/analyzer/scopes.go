@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"git-history-onboarding/internal/analysis/features"
+	"github.com/spf13/cobra"
+)
+
+func scopes(cmd *cobra.Command, args []string) {
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := loadConfig(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	_, commits, analyzer := setup(cmd)
+
+	unmapped := features.UnmappedScopes(commits, config.ScopeAliases)
+	if len(unmapped) == 0 {
+		fmt.Println("\nEvery conventional commit scope is in ScopeAliases")
+		return
+	}
+
+	// Where the commits of each unmapped scope end up today, to help choose an alias
+	landed := make(map[string]map[string]int)
+	for _, commit := range commits {
+		conventional := features.ParseConventionalCommit(commit.Commit.Message)
+		if conventional == nil {
+			continue
+		}
+		matches := analyzer.Explain(commit)
+		for _, scope := range conventional.Scopes {
+			scope = features.NormalizeScope(scope)
+			if landed[scope] == nil {
+				landed[scope] = make(map[string]int)
+			}
+			for _, match := range matches {
+				landed[scope][match.Feature]++
+			}
+		}
+	}
+
+	fmt.Println("\nUnmapped Scopes:")
+	for _, scope := range unmapped {
+		fmt.Printf("  - %s (%d commits)", scope.Scope, scope.Commits)
+		if names := topFeatures(landed[scope.Scope], 3); len(names) > 0 {
+			fmt.Printf(", currently assigned to %s", strings.Join(names, ", "))
+		}
+		fmt.Println()
+	}
+}
+
+// topFeatures returns the most frequent features, at most n
+func topFeatures(counts map[string]int, n int) []string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+	return names
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"git-history-onboarding/internal/report"
+	"git-history-onboarding/internal/server"
+	"github.com/spf13/cobra"
+)
+
+func serve(cmd *cobra.Command, args []string) {
+	addr, _ := cmd.Flags().GetString("addr")
+	load, _ := cmd.Flags().GetString("load")
+
+	var srv *server.Server
+	if load != "" {
+		saved, err := report.Load(load)
+		if err != nil {
+			log.Fatalf("Failed to load report: %v", err)
+		}
+		srv = server.NewFromReport(saved)
+	} else {
+		var err error
+		srv, err = server.New(context.Background(), func(ctx context.Context) (report.Report, error) {
+			return analyzeReport(ctx, cmd)
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	srv.Addr = addr
+
+	fmt.Printf("Serving on http://%s\n", addr)
+	if err := http.ListenAndServe(addr, srv.Handler()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// analyzeReport clones and analyzes the repository into a report; it runs on startup
+// and on every re-analysis
+func analyzeReport(ctx context.Context, cmd *cobra.Command) (report.Report, error) {
+	_, commits, analyzer, err := prepare(ctx, cmd)
+	if err != nil {
+		return report.Report{}, err
+	}
+	commits, featureAnalysis, err := analyzeCommits(ctx, cmd, analyzer, commits)
+	if err != nil {
+		return report.Report{}, err
+	}
+	repoURL, _ := cmd.Flags().GetString("repo")
+	return report.New(repoURL, commits, featureAnalysis), nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"git-history-onboarding/internal/analysis/exclusions"
+	"git-history-onboarding/internal/analysis/features"
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/issues"
+	"git-history-onboarding/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// setup clones the repository, reads its history and builds the feature analyzer
+// from the persistent flags shared by every command
+func setup(cmd *cobra.Command) (*git.Repository, []git.CommitInfo, *features.Analyzer) {
+	repo, commits, analyzer, err := prepare(context.Background(), cmd)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return repo, commits, analyzer
+}
+
+// prepare is setup returning its errors, for callers that must outlive a failure
+func prepare(ctx context.Context, cmd *cobra.Command) (*git.Repository, []git.CommitInfo, *features.Analyzer, error) {
+	repoURL, _ := cmd.Flags().GetString("repo")
+	strategy, _ := cmd.Flags().GetString("strategy")
+	configPath, _ := cmd.Flags().GetString("config")
+	if repoURL == "" {
+		return nil, nil, nil, errors.New(`required flag "repo" not set`)
+	}
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if len(config.Detectors) == 0 {
+		config.Detectors = []models.DetectorConfig{{Name: strategy}}
+	}
+
+	repo, err := git.Clone(ctx, repoURL)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to clone repository: %w", err)
+	}
+
+	commits, err := repo.GetCommitHistory()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get commit history: %w", err)
+	}
+
+	// On stderr, so that reports printed to stdout stay machine-readable
+	fmt.Fprintf(os.Stderr, "Found %d commits\n", len(commits))
+
+	registry, err := features.NewRegistryFromConfig(config, repo)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to set up feature detectors: %w", err)
+	}
+
+	filter, err := exclusions.NewFilter(append(exclusions.DefaultPatterns, config.ExcludePatterns...))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse exclusion patterns: %w", err)
+	}
+	if err := filter.LoadGitattributes(repo); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read .gitattributes: %w", err)
+	}
+
+	analyzer := features.NewAnalyzerWithRegistry(registry)
+	analyzer.Exclusions = filter
+	analyzer.Parents = config.FeatureParents
+	analyzer.Bugs, err = features.NewBugClassifierFromConfig(config)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to set up bug classification: %w", err)
+	}
+	patterns := config.IssuePatterns
+	if len(patterns) == 0 {
+		patterns = issues.Patterns(hashTracker(cmd), config.JiraProjects)
+	}
+	analyzer.Issues, err = issues.NewExtractor(patterns)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse issue patterns: %w", err)
+	}
+
+	return repo, commits, analyzer, nil
+}
+
+// analyzeCommits analyzes the history, first fetching the issues it references from the
+// trackers given on the command line so that their labels count towards bug fixes. It
+// also returns the commits without their excluded files, for reports to list the same
+// files the features were built from
+func analyzeCommits(ctx context.Context, cmd *cobra.Command, analyzer *features.Analyzer, commits []git.CommitInfo) ([]git.CommitInfo, map[string]*models.Feature, error) {
+	if analyzer.Exclusions != nil {
+		commits = analyzer.Exclusions.Apply(commits)
+	}
+	if err := fetchIssues(ctx, cmd, analyzer, commits); err != nil {
+		return nil, nil, err
+	}
+	return commits, analyzer.AnalyzeCommits(commits), nil
+}
+
+// fetchIssues fetches the issues the commits reference from the trackers given on the
+// command line and hands them to the analyzer, for commands that analyze parts of the
+// history themselves
+func fetchIssues(ctx context.Context, cmd *cobra.Command, analyzer *features.Analyzer, commits []git.CommitInfo) error {
+	enricher, err := issueEnricher(cmd)
+	if err != nil {
+		return err
+	}
+	if enricher != nil && analyzer.Issues != nil {
+		if err := enricher.Prefetch(ctx, analyzer.Issues, commits); err != nil {
+			return fmt.Errorf("failed to fetch referenced issues: %w", err)
+		}
+		analyzer.IssueDetails = enricher
+	}
+	return nil
+}
+
+// hashTracker is the tracker "#123" references belong to: GitLab when only
+// --gitlab-project is given, otherwise GitHub
+func hashTracker(cmd *cobra.Command) string {
+	githubRepo, _ := cmd.Flags().GetString("github-repo")
+	gitlabProject, _ := cmd.Flags().GetString("gitlab-project")
+	if gitlabProject != "" && githubRepo == "" {
+		return issues.TrackerGitLab
+	}
+	return issues.TrackerGitHub
+}
+
+// issueEnricher builds clients for the issue trackers given on the command line, with
+// tokens from GITHUB_TOKEN, GITLAB_TOKEN, JIRA_EMAIL and JIRA_TOKEN; it returns nil
+// when no tracker is configured
+func issueEnricher(cmd *cobra.Command) (*issues.Enricher, error) {
+	githubRepo, _ := cmd.Flags().GetString("github-repo")
+	githubURL, _ := cmd.Flags().GetString("github-url")
+	gitlabProject, _ := cmd.Flags().GetString("gitlab-project")
+	gitlabURL, _ := cmd.Flags().GetString("gitlab-url")
+	jiraURL, _ := cmd.Flags().GetString("jira-url")
+
+	clients := make(map[string]issues.Client)
+	if githubRepo != "" {
+		owner, name, ok := strings.Cut(githubRepo, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid --github-repo %q, expected owner/name", githubRepo)
+		}
+		clients[issues.TrackerGitHub] = &issues.GitHubClient{
+			BaseURL: githubURL,
+			Owner:   owner,
+			Repo:    name,
+			Token:   os.Getenv("GITHUB_TOKEN"),
+		}
+	}
+	if gitlabProject != "" {
+		clients[issues.TrackerGitLab] = &issues.GitLabClient{
+			BaseURL: gitlabURL,
+			Project: gitlabProject,
+			Token:   os.Getenv("GITLAB_TOKEN"),
+		}
+	}
+	if jiraURL != "" {
+		clients[issues.TrackerJira] = &issues.JiraClient{
+			BaseURL: jiraURL,
+			Email:   os.Getenv("JIRA_EMAIL"),
+			Token:   os.Getenv("JIRA_TOKEN"),
+		}
+	}
+
+	if len(clients) == 0 {
+		return nil, nil
+	}
+	return issues.NewEnricher(clients), nil
+}
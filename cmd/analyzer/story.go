@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"git-history-onboarding/internal/analysis/timeline"
+	"github.com/spf13/cobra"
+)
+
+func story(cmd *cobra.Command, args []string) {
+	gapDays, _ := cmd.Flags().GetInt("gap-days")
+	output, _ := cmd.Flags().GetString("output")
+
+	_, commits, analyzer := setup(cmd)
+	_, featureAnalysis, err := analyzeCommits(context.Background(), cmd, analyzer, commits)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var names []string
+	for name, feature := range featureAnalysis {
+		if strings.EqualFold(name, args[0]) {
+			opts := timeline.StoryOptions{Gap: time.Duration(gapDays) * 24 * time.Hour}
+			markdown := timeline.Tell(feature, opts).Markdown(changelogLinks(cmd).Commit)
+			writeOutput(output, []byte(markdown))
+			return
+		}
+		if len(feature.Commits) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	log.Fatalf("Feature not found: %s (features with commits: %s)", args[0], strings.Join(names, ", "))
+}
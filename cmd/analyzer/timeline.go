@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"git-history-onboarding/internal/analysis/timeline"
+	"git-history-onboarding/internal/display"
+	"github.com/spf13/cobra"
+)
+
+func timelineCommand(cmd *cobra.Command, args []string) {
+	repo, commits, analyzer := setup(cmd)
+	if err := fetchIssues(context.Background(), cmd, analyzer, commits); err != nil {
+		log.Fatal(err)
+	}
+
+	releases, err := timeline.Releases(repo, analyzer)
+	if err != nil {
+		log.Fatalf("Failed to build release timeline: %v", err)
+	}
+	if len(releases) == 0 {
+		fmt.Println("\nNo releases or commits found")
+		return
+	}
+
+	fmt.Println("\nRelease Timeline:")
+	for _, release := range releases {
+		fmt.Printf("\n%s (%s)", release.Tag, display.Date(release.Date))
+		if release.Previous != "" {
+			fmt.Printf(" since %s", release.Previous)
+		}
+		fmt.Printf(": %d commits, %d bug fixes, %d breaking changes\n",
+			len(release.Commits), len(release.BugFixes), len(release.BreakingChanges))
+
+		if len(release.Features) > 0 {
+			fmt.Println("Features:")
+			for _, activity := range release.Features {
+				fmt.Printf("  - %s (%d commits, %d bug fixes)\n", activity.Feature, activity.Commits, activity.BugFixes)
+			}
+		}
+		if len(release.BreakingChanges) > 0 {
+			fmt.Println("Breaking Changes:")
+			printBreakingChanges(release.BreakingChanges)
+		}
+		fmt.Println("Contributors:")
+		for _, contributor := range release.Contributors {
+			fmt.Printf("  - %s (%d commits)\n", contributor.AuthorEmail, contributor.Commits)
+		}
+	}
+}
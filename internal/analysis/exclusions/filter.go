@@ -0,0 +1,216 @@
+package exclusions
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"strings"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/glob"
+)
+
+// DefaultPatterns exclude vendored dependencies, lockfiles and generated code
+var DefaultPatterns = []string{
+	// Vendored dependencies
+	"vendor/",
+	"node_modules/",
+	"bower_components/",
+	"third_party/",
+	// Lockfiles
+	"go.sum",
+	"package-lock.json",
+	"npm-shrinkwrap.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"Cargo.lock",
+	"Gemfile.lock",
+	"composer.lock",
+	"poetry.lock",
+	"Pipfile.lock",
+	// Generated code
+	"*.pb.go",
+	"*.pb.gw.go",
+	"*_pb2.py",
+	"*_pb2_grpc.py",
+	"*.pb.cc",
+	"*.pb.h",
+	"*_pb.js",
+	"*_pb.d.ts",
+	"*.min.js",
+	"*.min.css",
+}
+
+// FileSource gives read access to the files of a repository at HEAD
+type FileSource interface {
+	ListFiles() ([]string, error)
+	ReadFile(path string) ([]byte, error)
+}
+
+type rule struct {
+	pattern *glob.Pattern
+	exclude bool
+}
+
+// Filter decides which files are left out of feature and ownership analysis.
+// Rules are evaluated in order and the last matching rule wins, so later rules
+// (including .gitattributes) can re-include files excluded by the defaults
+type Filter struct {
+	rules []rule
+}
+
+// NewFilter builds a filter from glob patterns; a pattern prefixed with "!" re-includes matching files
+func NewFilter(patterns []string) (*Filter, error) {
+	f := &Filter{}
+	for _, pattern := range patterns {
+		exclude := !strings.HasPrefix(pattern, "!")
+		if err := f.add(strings.TrimPrefix(pattern, "!"), exclude); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Default returns a filter using DefaultPatterns
+func Default() *Filter {
+	f, err := NewFilter(DefaultPatterns)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func (f *Filter) add(pattern string, exclude bool) error {
+	compiled, err := glob.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	f.rules = append(f.rules, rule{pattern: compiled, exclude: exclude})
+	return nil
+}
+
+// AddGitattributes honors linguist-generated and linguist-vendored attributes from a
+// .gitattributes file located in dir ("" for the repository root)
+func (f *Filter) AddGitattributes(dir string, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		for _, attr := range fields[1:] {
+			exclude, ok := linguistAttribute(attr)
+			if !ok {
+				continue
+			}
+			if err := f.add(scopePattern(dir, fields[0]), exclude); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// LoadGitattributes reads every .gitattributes file in src, parents before children
+func (f *Filter) LoadGitattributes(src FileSource) error {
+	files, err := src.ListFiles()
+	if err != nil {
+		return err
+	}
+
+	var attributeFiles []string
+	for _, file := range files {
+		if path.Base(file) == ".gitattributes" {
+			attributeFiles = append(attributeFiles, file)
+		}
+	}
+	// Shallower files first so nested ones take precedence
+	for depth := 0; len(attributeFiles) > 0; depth++ {
+		remaining := attributeFiles[:0]
+		for _, file := range attributeFiles {
+			if strings.Count(file, "/") != depth {
+				remaining = append(remaining, file)
+				continue
+			}
+			data, err := src.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			dir := path.Dir(file)
+			if dir == "." {
+				dir = ""
+			}
+			if err := f.AddGitattributes(dir, data); err != nil {
+				return err
+			}
+		}
+		attributeFiles = remaining
+	}
+	return nil
+}
+
+// linguistAttribute reports whether attr sets (true) or unsets (false) a linguist exclusion
+func linguistAttribute(attr string) (exclude bool, ok bool) {
+	name, value, hasValue := strings.Cut(attr, "=")
+	unset := strings.HasPrefix(name, "-") || strings.HasPrefix(name, "!")
+	name = strings.TrimLeft(name, "-!")
+	if name != "linguist-generated" && name != "linguist-vendored" {
+		return false, false
+	}
+	if unset {
+		return false, true
+	}
+	if hasValue {
+		return value == "true" || value == "1", true
+	}
+	return true, true
+}
+
+// scopePattern makes a pattern from a nested .gitattributes relative to the repository root
+func scopePattern(dir, pattern string) string {
+	if dir == "" {
+		return pattern
+	}
+	if strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		return dir + "/" + strings.TrimPrefix(pattern, "/")
+	}
+	return dir + "/**/" + pattern
+}
+
+// Excluded reports whether a file should be left out of analysis
+func (f *Filter) Excluded(file string) bool {
+	excluded := false
+	for _, r := range f.rules {
+		if r.pattern.Match(file) {
+			excluded = r.exclude
+		}
+	}
+	return excluded
+}
+
+// Apply returns the commits with excluded files removed. Commits that only touched
+// excluded files are dropped entirely
+func (f *Filter) Apply(commits []git.CommitInfo) []git.CommitInfo {
+	result := make([]git.CommitInfo, 0, len(commits))
+	for _, commit := range commits {
+		if len(commit.Files) == 0 {
+			result = append(result, commit)
+			continue
+		}
+
+		files := make([]string, 0, len(commit.Files))
+		for _, file := range commit.Files {
+			if !f.Excluded(file) {
+				files = append(files, file)
+			}
+		}
+		if len(files) == 0 {
+			continue
+		}
+
+		commit.Files = files
+		result = append(result, commit)
+	}
+	return result
+}
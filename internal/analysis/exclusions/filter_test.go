@@ -0,0 +1,122 @@
+package exclusions
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestCommit(hash string, message string, files []string) git.CommitInfo {
+	return git.CommitInfo{
+		Commit: &object.Commit{
+			Hash: plumbing.NewHash(hash),
+			Author: object.Signature{
+				Name:  "Test User",
+				Email: "test@example.com",
+				When:  time.Now(),
+			},
+			Message: message,
+		},
+		Files: files,
+	}
+}
+
+// fakeSource serves files from memory
+type fakeSource map[string]string
+
+func (f fakeSource) ListFiles() ([]string, error) {
+	files := make([]string, 0, len(f))
+	for name := range f {
+		files = append(files, name)
+	}
+	return files, nil
+}
+
+func (f fakeSource) ReadFile(path string) ([]byte, error) {
+	contents, ok := f[path]
+	if !ok {
+		return nil, fmt.Errorf("file not found: %s", path)
+	}
+	return []byte(contents), nil
+}
+
+func TestDefaultFilter(t *testing.T) {
+	filter := Default()
+
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"go.sum", true},
+		{"tools/go.sum", true},
+		{"vendor/github.com/pkg/errors/errors.go", true},
+		{"web/node_modules/react/index.js", true},
+		{"api/v1/service.pb.go", true},
+		{"proto/service_pb2.py", true},
+		{"web/package-lock.json", true},
+		{"go.mod", false},
+		{"internal/auth/login.go", false},
+		{"docs/vendor-policy.md", false},
+		{"vendor", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.expected, filter.Excluded(tc.path))
+		})
+	}
+}
+
+func TestNewFilterNegation(t *testing.T) {
+	filter, err := NewFilter(append(DefaultPatterns, "*.snap", "!vendor/acme/"))
+	assert.NoError(t, err)
+
+	assert.True(t, filter.Excluded("ui/__snapshots__/button.snap"))
+	assert.True(t, filter.Excluded("vendor/other/x.go"))
+	assert.False(t, filter.Excluded("vendor/acme/x.go"))
+}
+
+func TestLoadGitattributes(t *testing.T) {
+	filter := Default()
+	err := filter.LoadGitattributes(fakeSource{
+		".gitattributes": `# Generated sources
+*.gen.ts linguist-generated
+docs/api/** linguist-generated=true
+vendor/acme/** -linguist-vendored
+*.go text eol=lf
+`,
+		"web/.gitattributes": "fixtures/ linguist-vendored\n",
+	})
+	assert.NoError(t, err)
+
+	assert.True(t, filter.Excluded("web/src/client.gen.ts"))
+	assert.True(t, filter.Excluded("docs/api/index.html"))
+	assert.False(t, filter.Excluded("vendor/acme/lib.go"))
+	assert.True(t, filter.Excluded("vendor/other/lib.go"))
+	assert.True(t, filter.Excluded("web/test/fixtures/data.json"))
+	assert.False(t, filter.Excluded("fixtures/data.json"))
+	assert.False(t, filter.Excluded("main.go"))
+}
+
+func TestApply(t *testing.T) {
+	filter := Default()
+
+	commits := []git.CommitInfo{
+		createTestCommit("abc123", "feat(auth): login", []string{"auth/login.go", "go.sum"}),
+		createTestCommit("def456", "chore: bump deps", []string{"go.sum", "vendor/x/y.go"}),
+		createTestCommit("789abc", "merge", nil),
+	}
+
+	filtered := filter.Apply(commits)
+	assert.Len(t, filtered, 2)
+	assert.Equal(t, []string{"auth/login.go"}, filtered[0].Files)
+	assert.Equal(t, "merge", filtered[1].Commit.Message)
+
+	// The input is left untouched
+	assert.Equal(t, []string{"auth/login.go", "go.sum"}, commits[0].Files)
+}
@@ -1,86 +1,73 @@
 package features
 
 import (
-	"path/filepath"
-	"regexp"
-	"strings"
-
 	"git-history-onboarding/internal/git"
 	"git-history-onboarding/internal/models"
 	"git-history-onboarding/internal/analysis/ownership"
+	"git-history-onboarding/internal/analysis/exclusions"
+	"git-history-onboarding/internal/issues"
 )
 
 type Analyzer struct {
-	featurePatterns map[string][]*regexp.Regexp
+	Exclusions *exclusions.Filter // files left out of analysis; nil keeps every file
+	Parents    map[string]string  // child -> parent feature, on top of "A > B" names
+	Issues     *issues.Extractor  // finds issue references in commit messages; nil skips them
+	IssueDetails *issues.Enricher // fills in prefetched issue labels and dates before classifying; nil leaves references bare
+	Bugs       *BugClassifier     // decides which commits are bug fixes; nil uses DefaultBugClassifier
+	CancelReverts bool            // leave reverts and the commits they undid out of commits and ownership
+	registry *Registry
 	ownershipAnalyzer *ownership.Analyzer
 }
 
-type ConventionalCommit struct {
-	Type        string
-	Scope       string
-	Description string
-	Body        string
-	Breaking    bool
-}
-
 func NewAnalyzer() *Analyzer {
-	patterns := map[string][]string{
-		"Authentication": {`auth`, `login`, `oauth`, `sign[ui][pn]`, `signout`},
-		"User Profile": {`profile`, `user[-_]?(?:profile|settings|management|dashboard)?`, `account`},
-		"API": {`api(?:[-_](?:gateway|client|server|docs|documentation))?`, `graphql`, `rest`},
-		"Database": {`db`, `database`, `storage`, `sql`, `nosql`, `orm`, `migration`},
-		"UI": {`ui`, `interface`, `component`, `theme`, `style`, `css`, `html`, `javascript`, `react`, `vue`, `angular`, `svelte`, `tailwind`, `bootstrap`},
-		"Tests": {`test`, `spec`, `_test\.go$`},
-		"Security": {`auth`, `security`, `authentication`, `authorization`, `encrypt(?:ion)?`, `hash(?:ing)?`, `password`, `token`, `jwt`, `api[-_](?:key|token|secret)`},
-        "Notifications":{`notification`, `notifier`, `notify`, `alert`, `toast`, `snackbar`},
-        "Analytics": {`analytics`, `tracking`, `telemetry`, `metrics`, `stats`, `logger`, `logging`},
-        "Cache": {`cache`, `memcached`, `redis`, `caching`},
-        "Search": {`search`, `indexing`, `fulltext`, `autocomplete`, `filter`, `sort`},
-        "Payment": {`payment`, `billing`, `subscription`, `invoice`, `purchase`},
-        "Admin": {`admin`, `dashboard`, `management`, `control`, `panel`},
-        "Monitoring": {`monitor`, `observe`, `stats`, `metrics`, `logging`, `tracing`},
-        "Logging": {`log`, `logger`, `logging`, `syslog`, `journald`},
-        "Configuration": {`config`, `configuration`, `settings`, `properties`, `properties`},
-        "Scheduling": {`schedule`, `scheduler`, `cron`, `job`, `task`},
-        "Caching": {`cache`, `memcached`, `redis`, `caching`},
-        "Rate Limiting": {`rate`, `limit`, `limiter`, `throttle`},
-        "Documentation": {`docs`, `documentation`, `readme`, `changelog`, `release`, `upgrade`, `migration`},
-	}
-
-	compiledPatterns := make(map[string][]*regexp.Regexp)
-	for feature, patternList := range patterns {
-		compiledPatterns[feature] = make([]*regexp.Regexp, 0, len(patternList))
-		for _, pattern := range patternList {
-			regex := regexp.MustCompile(`(?i)` + pattern)  // (?i) makes it case-insensitive
-			compiledPatterns[feature] = append(compiledPatterns[feature], regex)
-		}
-	}
+	registry := NewRegistry(MergeUnion)
+	registry.Register(NewRegexDetector(DefaultFeaturePatterns()), 0)
+	return NewAnalyzerWithRegistry(registry)
+}
 
+// NewAnalyzerWithRegistry returns an analyzer that assigns features using the given detectors
+func NewAnalyzerWithRegistry(registry *Registry) *Analyzer {
 	return &Analyzer{
-		featurePatterns: compiledPatterns,
+		Exclusions: exclusions.Default(),
+		Issues: issues.DefaultExtractor(),
+		Bugs: DefaultBugClassifier(),
+		registry: registry,
 		ownershipAnalyzer: ownership.NewAnalyzer(0.2, 0.1),
 	}
 }
 
+// NewModuleAnalyzer returns an analyzer that uses the given modules as features,
+// assigning each commit to the modules containing its files
+func NewModuleAnalyzer(modules []Module) *Analyzer {
+	registry := NewRegistry(MergeUnion)
+	registry.Register(NewModuleDetector(modules), 0)
+	return NewAnalyzerWithRegistry(registry)
+}
+
 func (a *Analyzer) AnalyzeCommits(commits []git.CommitInfo) map[string]*models.Feature {
 	features := make(map[string]*models.Feature)
 
 	// Initialize features
-	for name := range a.featurePatterns {
-		features[name] = &models.Feature{
-			Name:         name,
-			Owners:       make(map[string]float64),
-			BackupOwners: make(map[string]float64),
-			Commits:      make([]git.CommitInfo, 0),
-			Bugs:         make([]models.Bug, 0),
-		}
+	for _, name := range a.registry.Features() {
+		features[name] = newFeature(name)
 	}
 
+	// Drop vendored, generated and lockfile changes before matching
+	if a.Exclusions != nil {
+		commits = a.Exclusions.Apply(commits)
+	}
+
+	// Link reverts to the commits they undid
+	reverts := indexReverts(commits, a.CancelReverts)
+
 	// Analyze each commit
 	for _, commit := range commits {
-		a.processCommit(commit, features)
+		a.processCommit(commit, features, reverts)
 	}
 
+	// Link sub-features to their parents and roll their history up
+	buildHierarchy(features, a.Parents)
+
 	// Update ownership for each feature
 	for _, feature := range features {
 		a.ownershipAnalyzer.UpdateFeatureOwnership(feature)
@@ -89,81 +76,107 @@ func (a *Analyzer) AnalyzeCommits(commits []git.CommitInfo) map[string]*models.F
 	return features
 }
 
-func (a *Analyzer) processCommit(commit git.CommitInfo, features map[string]*models.Feature) {
-	// Parse conventional commit format
-	conventionalCommit := parseConventionalCommit(commit.Commit.Message)
-	
-	for featureName, patterns := range a.featurePatterns {
-		feature := features[featureName]
-		matchFound := false
-
-		// Check conventional commit scope if available
-		if conventionalCommit != nil && conventionalCommit.Scope != "" {
-			matchFound = a.matchesFeature(conventionalCommit.Scope, patterns)
+// Explain returns the features a commit would be assigned to and why, or nil when
+// every file it changes is excluded
+func (a *Analyzer) Explain(commit git.CommitInfo) []FeatureMatch {
+	if a.Exclusions != nil {
+		filtered := a.Exclusions.Apply([]git.CommitInfo{commit})
+		if len(filtered) == 0 {
+			return nil
 		}
+		commit = filtered[0]
+	}
+	return a.registry.Detect(commit)
+}
 
-		// If no match found in scope, check the description and body
-		if !matchFound && conventionalCommit != nil {
-			matchFound = a.matchesFeature(conventionalCommit.Description, patterns) ||
-						a.matchesFeature(conventionalCommit.Body, patterns)
+func newFeature(name string) *models.Feature {
+	return &models.Feature{
+		Name:         name,
+		Owners:       make(map[string]float64),
+		BackupOwners: make(map[string]float64),
+		Commits:      make([]git.CommitInfo, 0),
+		Bugs:         make([]models.Bug, 0),
+		Assignments:  make(map[string]models.Assignment),
+		IssueRefs:    make(map[string][]models.IssueRef),
+	}
+}
+
+func (a *Analyzer) processCommit(commit git.CommitInfo, features map[string]*models.Feature, reverts revertIndex) {
+	hash := commit.Commit.Hash.String()
+	revert, isRevert := reverts.reverts[hash]
+	if reverts.cancelled[hash] && !isRevert {
+		return
+	}
+
+	var issueRefs []models.IssueRef
+	if a.Issues != nil {
+		issueRefs = a.Issues.Extract(commit.Commit.Message)
+	}
+	if a.IssueDetails != nil {
+		issueRefs = a.IssueDetails.Resolve(issueRefs)
+	}
+	isBug := !isRevert && a.bugs().Score(commit.Commit.Message, commit.Files, issueRefs).IsBug()
+	breaking, isBreaking := ParseBreakingChange(commit)
+
+	for _, match := range a.registry.Detect(commit) {
+		feature, exists := features[match.Feature]
+		if !exists {
+			// Detectors without a fixed feature list introduce features as they match
+			feature = newFeature(match.Feature)
+			features[match.Feature] = feature
 		}
 
-		// If still no match, check the files
-		if !matchFound {
-			for _, file := range commit.Files {
-				if a.matchesFeature(file, patterns) {
-					matchFound = true
-					break
-				}
+		if isRevert {
+			feature.Reverts = append(feature.Reverts, revert)
+			if revert.Cancelled {
+				continue
 			}
 		}
 
-		if matchFound {
-			// Update feature information
-			if feature.CreatedAt.IsZero() || commit.Commit.Author.When.Before(feature.CreatedAt) {
-				feature.CreatedAt = commit.Commit.Author.When
-			}
-			if commit.Commit.Author.When.After(feature.LastUpdated) {
-				feature.LastUpdated = commit.Commit.Author.When
-			}
+		// Update feature information
+		if feature.CreatedAt.IsZero() || commit.Commit.Author.When.Before(feature.CreatedAt) {
+			feature.CreatedAt = commit.Commit.Author.When
+		}
+		if commit.Commit.Author.When.After(feature.LastUpdated) {
+			feature.LastUpdated = commit.Commit.Author.When
+		}
 
-			feature.Commits = append(feature.Commits, commit)
-
-			// Check for bug fixes (now including conventional commit type)
-			if conventionalCommit != nil && conventionalCommit.Type == "fix" || 
-			   a.isBugFix(commit.Commit.Message) {
-				feature.Bugs = append(feature.Bugs, models.Bug{
-					Description:   commit.Commit.Message,
-					FixedAt:      commit.Commit.Author.When,
-					CommitHash:   commit.Commit.Hash.String(),
-					AuthorEmail:  commit.Commit.Author.Email,
-					AffectedFiles: commit.Files,
-				})
-			}
+		feature.Commits = append(feature.Commits, commit)
+		feature.Assignments[commit.Commit.Hash.String()] = match.Assignment()
+		if len(issueRefs) > 0 {
+			feature.IssueRefs[commit.Commit.Hash.String()] = issueRefs
+		}
+
+		if isBreaking {
+			feature.BreakingChanges = append(feature.BreakingChanges, breaking)
 		}
-	}
-}
 
-func (a *Analyzer) matchesFeature(file string, patterns []*regexp.Regexp) bool {
-	normalizedPath := filepath.ToSlash(file)
-	for _, pattern := range patterns {
-		if pattern.MatchString(normalizedPath) {
-			return true
+		if isBug {
+			feature.Bugs = append(feature.Bugs, models.Bug{
+				Description:   commit.Commit.Message,
+				FixedAt:      commit.Commit.Author.When,
+				ReportedAt:   issues.EarliestCreated(issueRefs),
+				CommitHash:   commit.Commit.Hash.String(),
+				AuthorEmail:  commit.Commit.Author.Email,
+				AffectedFiles: commit.Files,
+				Issues:       issueRefs,
+			})
 		}
 	}
-	return false
 }
 
 func (a *Analyzer) isBugFix(message string) bool {
-	message = strings.ToLower(message)
-	bugKeywords := []string{"fix", "bug", "issue", "resolve", "patch"}
-	
-	for _, keyword := range bugKeywords {
-		if strings.Contains(message, keyword) {
-			return true
-		}
+	return a.bugs().Score(message, nil, nil).IsBug()
+}
+
+// defaultBugs classifies bugs for analyzers without a classifier
+var defaultBugs = DefaultBugClassifier()
+
+func (a *Analyzer) bugs() *BugClassifier {
+	if a.Bugs == nil {
+		return defaultBugs
 	}
-	return false
+	return a.Bugs
 }
 
 func (a *Analyzer) getAuthorCommitCounts(commits []git.CommitInfo) map[string]int {
@@ -172,50 +185,4 @@ func (a *Analyzer) getAuthorCommitCounts(commits []git.CommitInfo) map[string]in
 		counts[commit.Commit.Author.Email]++  // Using email instead of name
 	}
 	return counts
-}
-
-func parseConventionalCommit(message string) *ConventionalCommit {
-	// Matches: <type>[optional scope][!]: <description>
-	conventionalPattern := regexp.MustCompile(`^(?P<type>\w+)(?:\((?P<scope>[\w-]+)\))?(?P<breaking>!)?:\s*(?P<description>.+)`)
-	
-	lines := strings.Split(message, "\n")
-	if len(lines) == 0 {
-		return nil
-	}
-
-	matches := conventionalPattern.FindStringSubmatch(lines[0])
-	if matches == nil {
-		return nil
-	}
-
-	// Get named capture groups
-	typeIdx := conventionalPattern.SubexpIndex("type")
-	scopeIdx := conventionalPattern.SubexpIndex("scope")
-	breakingIdx := conventionalPattern.SubexpIndex("breaking")
-	descIdx := conventionalPattern.SubexpIndex("description")
-
-	commit := &ConventionalCommit{
-		Type:        matches[typeIdx],
-		Description: matches[descIdx],
-		Breaking:    matches[breakingIdx] != "",
-	}
-
-	if scopeIdx >= 0 && scopeIdx < len(matches) {
-		commit.Scope = matches[scopeIdx]
-	}
-
-	// Check for body and BREAKING CHANGE footer
-	if len(lines) > 1 {
-		var bodyLines []string
-		for _, line := range lines[1:] {
-			if strings.HasPrefix(line, "BREAKING CHANGE:") {
-				commit.Breaking = true
-				continue
-			}
-			bodyLines = append(bodyLines, line)
-		}
-		commit.Body = strings.TrimSpace(strings.Join(bodyLines, "\n"))
-	}
-
-	return commit
-} 
\ No newline at end of file
+}
\ No newline at end of file
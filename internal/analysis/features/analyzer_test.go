@@ -2,6 +2,7 @@ package features
 
 import (
 	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
 	"regexp"
 	"testing"
 	"time"
@@ -41,8 +42,12 @@ func TestNewAnalyzer(t *testing.T) {
 		"Documentation",
 	}
 
+	detector := NewRegexDetector(DefaultFeaturePatterns())
+	registered := analyzer.registry.Features()
+
 	for _, feature := range expectedFeatures {
-		if patterns, exists := analyzer.featurePatterns[feature]; !exists {
+		assert.Contains(t, registered, feature)
+		if patterns, exists := detector.patterns[feature]; !exists {
 			t.Errorf("Expected feature %s patterns to exist", feature)
 		} else if len(patterns) == 0 {
 			t.Errorf("Expected feature %s to have patterns", feature)
@@ -177,7 +182,7 @@ func getHighestOwner(owners map[string]float64) string {
 }
 
 func TestFeaturePatternMatching(t *testing.T) {
-	analyzer := NewAnalyzer()
+	detector := NewRegexDetector(DefaultFeaturePatterns())
 
 	testCases := []struct {
 		name     string
@@ -194,8 +199,8 @@ func TestFeaturePatternMatching(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			patterns := analyzer.featurePatterns[tc.feature]
-			matched := analyzer.matchesFeature(tc.path, patterns)
+			patterns := detector.patterns[tc.feature]
+			matched := matchesFeature(tc.path, patterns)
 			assert.Equal(t, tc.expected, matched)
 		})
 	}
@@ -234,6 +239,7 @@ func TestParseConventionalCommit(t *testing.T) {
 				Scope:       "api",
 				Description: "redesign API",
 				Breaking:    true,
+				BreakingDescription: "redesign API",
 			},
 		},
 		{
@@ -246,8 +252,9 @@ Migration required`,
 				Type:        "feat",
 				Scope:       "db",
 				Description: "add new database",
-				Body:        "Migration required",
 				Breaking:    true,
+				// Footer values continue on the following lines
+				BreakingDescription: "This changes the database schema\nMigration required",
 			},
 		},
 		{
@@ -259,7 +266,7 @@ Migration required`,
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := parseConventionalCommit(tc.message)
+			result := ParseConventionalCommit(tc.message)
 
 			if tc.expected == nil {
 				if result != nil {
@@ -289,12 +296,17 @@ Migration required`,
 				t.Errorf("Expected breaking %v, got %v", 
 					tc.expected.Breaking, result.Breaking)
 			}
+
+			if result.BreakingDescription != tc.expected.BreakingDescription {
+				t.Errorf("Expected breaking description %s, got %s",
+					tc.expected.BreakingDescription, result.BreakingDescription)
+			}
 		})
 	}
 }
 
 func TestMatchesFeature(t *testing.T) {
-	analyzer := NewAnalyzer()
+	detector := NewRegexDetector(DefaultFeaturePatterns())
 
 	testCases := []struct {
 		name     string
@@ -305,26 +317,26 @@ func TestMatchesFeature(t *testing.T) {
 		{
 			name:     "Auth file match",
 			input:    "src/auth/login.go",
-			patterns: analyzer.featurePatterns["Authentication"],
+			patterns: detector.patterns["Authentication"],
 			expected: true,
 		},
 		{
 			name:     "API file match",
 			input:    "api/endpoint.go",
-			patterns: analyzer.featurePatterns["API"],
+			patterns: detector.patterns["API"],
 			expected: true,
 		},
 		{
 			name:     "No match",
 			input:    "random/file.go",
-			patterns: analyzer.featurePatterns["Authentication"],
+			patterns: detector.patterns["Authentication"],
 			expected: false,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := analyzer.matchesFeature(tc.input, tc.patterns)
+			result := matchesFeature(tc.input, tc.patterns)
 			if result != tc.expected {
 				t.Errorf("Expected %v for input %s", tc.expected, tc.input)
 			}
@@ -365,4 +377,126 @@ func TestIsBugFix(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+} 
+func TestExplain(t *testing.T) {
+	analyzer := NewAnalyzer()
+	now := time.Now()
+
+	testCases := []struct {
+		name    string
+		commit  git.CommitInfo
+		feature string
+		rule    string
+		pattern string
+		text    string
+	}{
+		{
+			name:    "Scope",
+			commit:  createTestCommit("abc123", "feat(auth): add sso", "John Doe", "john@example.com", now, nil),
+			feature: "Authentication",
+			rule:    RuleScope,
+			pattern: "auth",
+			text:    "auth",
+		},
+		{
+			name:    "Description",
+			commit:  createTestCommit("def456", "feat: cache sessions in redis", "John Doe", "john@example.com", now, nil),
+			feature: "Cache",
+			rule:    RuleDescription,
+			pattern: "cache",
+			text:    "cache sessions in redis",
+		},
+		{
+			name:    "File path",
+			commit:  createTestCommit("789abc", "update schema", "John Doe", "john@example.com", now, []string{"db/schema.go"}),
+			feature: "Database",
+			rule:    RuleFile,
+			pattern: "db",
+			text:    "db/schema.go",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var found *FeatureMatch
+			for _, match := range analyzer.Explain(tc.commit) {
+				if match.Feature == tc.feature {
+					match := match
+					found = &match
+				}
+			}
+			if !assert.NotNil(t, found, "expected a match for %s", tc.feature) {
+				return
+			}
+			assert.Equal(t, "patterns", found.Detector)
+			assert.Equal(t, tc.rule, found.Rule)
+			assert.Equal(t, tc.pattern, found.Pattern)
+			assert.Equal(t, tc.text, found.Text)
+			assert.Greater(t, found.Confidence, 0.0)
+		})
+	}
+
+	t.Run("Scope is more confident than files", func(t *testing.T) {
+		features := analyzer.AnalyzeCommits([]git.CommitInfo{
+			createTestCommit("aaa111", "feat(api): add endpoint", "John Doe", "john@example.com", now, nil),
+			createTestCommit("bbb222", "add endpoint", "John Doe", "john@example.com", now, []string{"api/routes.go"}),
+		})
+
+		assignments := features["API"].Assignments
+		assert.Len(t, assignments, 2)
+		assert.Greater(t, assignments[plumbing.NewHash("aaa111").String()].Confidence,
+			assignments[plumbing.NewHash("bbb222").String()].Confidence)
+	})
+}
+
+func TestAnalyzeCommitsExclusions(t *testing.T) {
+	now := time.Now()
+	commits := []git.CommitInfo{
+		createTestCommit("abc123", "chore: bump dependencies", "Bot", "bot@example.com", now,
+			[]string{"go.sum", "vendor/github.com/go-sql-driver/mysql/conn.go"}),
+		createTestCommit("def456", "update settings", "John Doe", "john@example.com", now,
+			[]string{"config/settings.go", "api/v1/config.pb.go"}),
+	}
+
+	features := NewAnalyzer().AnalyzeCommits(commits)
+	for name, feature := range features {
+		for _, commit := range feature.Commits {
+			assert.NotEqual(t, "bot@example.com", commit.Commit.Author.Email, "excluded commit matched %s", name)
+		}
+	}
+	assert.Len(t, features["Configuration"].Commits, 1)
+	assert.Equal(t, []string{"config/settings.go"}, features["Configuration"].Commits[0].Files)
+
+	t.Run("Disabled", func(t *testing.T) {
+		analyzer := NewAnalyzer()
+		analyzer.Exclusions = nil
+
+		features := analyzer.AnalyzeCommits(commits)
+		assert.Len(t, features["Database"].Commits, 1, "vendored mysql driver matches the sql pattern")
+	})
+
+	t.Run("Explain", func(t *testing.T) {
+		analyzer := NewAnalyzer()
+		assert.Empty(t, analyzer.Explain(commits[0]), "fully excluded commit")
+
+		for _, match := range analyzer.Explain(commits[1]) {
+			assert.NotEqual(t, "api/v1/config.pb.go", match.Text, "excluded file matched %s", match.Feature)
+		}
+	})
+}
+
+func TestAnalyzeCommitsIssueRefs(t *testing.T) {
+	now := time.Now()
+	commits := []git.CommitInfo{
+		createTestCommit("abc123", "fix(auth): reject expired tokens (#42)", "John Doe", "john@example.com", now, nil),
+		createTestCommit("def456", "feat(auth): add sso\n\nRefs: AUTH-7", "John Doe", "john@example.com", now, nil),
+	}
+
+	auth := NewAnalyzer().AnalyzeCommits(commits)["Authentication"]
+	fixRefs := []models.IssueRef{{Tracker: "github", ID: "42"}}
+	assert.Equal(t, fixRefs, auth.IssueRefs[commits[0].Commit.Hash.String()])
+	assert.Equal(t, []models.IssueRef{{Tracker: "jira", ID: "AUTH-7"}}, auth.IssueRefs[commits[1].Commit.Hash.String()])
+	if assert.Len(t, auth.Bugs, 1) {
+		assert.Equal(t, fixRefs, auth.Bugs[0].Issues)
+	}
+}
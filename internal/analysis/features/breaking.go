@@ -0,0 +1,32 @@
+package features
+
+import (
+	"sort"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+)
+
+// ParseBreakingChange returns the breaking change a conventional commit announces, if any
+func ParseBreakingChange(commit git.CommitInfo) (models.BreakingChange, bool) {
+	conventional := ParseConventionalCommit(commit.Commit.Message)
+	if conventional == nil || !conventional.Breaking {
+		return models.BreakingChange{}, false
+	}
+	return models.BreakingChange{
+		CommitHash:  commit.Commit.Hash.String(),
+		Description: conventional.BreakingDescription,
+		AuthorEmail: commit.Commit.Author.Email,
+		Date:        commit.Commit.Author.When,
+	}, true
+}
+
+// BreakingTimeline returns a feature's breaking changes, oldest first
+func BreakingTimeline(feature *models.Feature) []models.BreakingChange {
+	timeline := make([]models.BreakingChange, len(feature.BreakingChanges))
+	copy(timeline, feature.BreakingChanges)
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Date.Before(timeline[j].Date)
+	})
+	return timeline
+}
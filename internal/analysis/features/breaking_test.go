@@ -0,0 +1,34 @@
+package features
+
+import (
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeCommitsBreakingChanges(t *testing.T) {
+	now := time.Now()
+	commits := []git.CommitInfo{
+		createTestCommit("ccc333", "feat(auth): add sso", "John Doe", "john@example.com", now, nil),
+		createTestCommit("bbb222", "feat(auth)!: drop basic auth", "Jane Smith", "jane@example.com", now.Add(-time.Hour), nil),
+		createTestCommit("aaa111", "refactor(auth): rename session fields\n\nBREAKING CHANGE: session cookies are invalidated",
+			"John Doe", "john@example.com", now.Add(-2*time.Hour), nil),
+	}
+
+	auth := NewAnalyzer().AnalyzeCommits(commits)["Authentication"]
+	timeline := BreakingTimeline(auth)
+	if assert.Len(t, timeline, 2) {
+		assert.Equal(t, commits[2].Commit.Hash.String(), timeline[0].CommitHash)
+		assert.Equal(t, "session cookies are invalidated", timeline[0].Description)
+		assert.Equal(t, "john@example.com", timeline[0].AuthorEmail)
+
+		assert.Equal(t, "drop basic auth", timeline[1].Description)
+		assert.Equal(t, now.Add(-time.Hour), timeline[1].Date)
+	}
+	assert.Equal(t, commits[1].Commit.Hash.String(), auth.BreakingChanges[0].CommitHash, "recorded in history order")
+
+	_, ok := ParseBreakingChange(commits[0])
+	assert.False(t, ok)
+}
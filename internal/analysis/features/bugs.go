@@ -0,0 +1,171 @@
+package features
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"git-history-onboarding/internal/issues"
+	"git-history-onboarding/internal/models"
+)
+
+// Weights of the signals combined into a bug-fix score
+const (
+	bugTypeWeight        = 0.6  // conventional "fix" type or a configured bug prefix
+	subjectKeywordWeight = 0.5  // bug keyword in the subject line
+	bodyKeywordWeight    = 0.3  // bug keyword only in the body
+	bugLabelWeight       = 0.6  // referenced issue labeled as a bug
+	testFileWeight       = 0.2  // the commit also touches tests
+	nonBugTypeWeight     = -0.6 // conventional type that never fixes behavior, e.g. "docs"
+	nonBugPatternWeight  = -0.6 // matches a negative pattern, e.g. "fix typo"
+)
+
+// BugThreshold is the score from which a commit is classified as a bug fix
+const BugThreshold = 0.5
+
+// DefaultBugPrefixes mark a bug fix at the start of a subject, besides conventional fix types
+var DefaultBugPrefixes = []string{"bug:", "[bug]", "[fix]", "hotfix:"}
+
+// DefaultNonBugPatterns recognize "fixes" that do not fix behavior
+var DefaultNonBugPatterns = []string{
+	`(?i)^merge\b`,
+	`(?i)\b(typos?|spelling|whitespace|formatting|indentation|lint(er|ing)?|gofmt|prettier)\b`,
+	`(?i)\bfix(es|ed|ing)?\s+(the\s+)?(tests?|ci|build|docs?|readme|comments?|changelog|merge conflicts?)\b`,
+}
+
+var (
+	bugTypes    = map[string]bool{"fix": true, "bugfix": true, "hotfix": true}
+	nonBugTypes = map[string]bool{"docs": true, "style": true, "chore": true, "ci": true, "build": true, "test": true}
+	bugKeywords = regexp.MustCompile(`(?i)\b(fix(es|ed|ing)?|bug(s|fix)?|hotfix|crash(es|ed|ing)?|regression|defect|broken|npe|segfault|panic(s|ked)?)\b`)
+	testFile    = regexp.MustCompile(`(^|/)(tests?|__tests__|spec)/|_test\.\w+$|(^|/)test_[^/]+\.py$|\.(test|spec)\.\w+$`)
+)
+
+// BugScore is the outcome of classifying a commit, with the signals that contributed
+type BugScore struct {
+	Score   float64
+	Signals []string
+}
+
+// IsBug reports whether the score reaches BugThreshold
+func (s BugScore) IsBug() bool {
+	return s.Score >= BugThreshold
+}
+
+func (s *BugScore) add(weight float64, signal string) {
+	s.Score += weight
+	s.Signals = append(s.Signals, signal)
+}
+
+// BugClassifier decides whether a commit fixes a bug from a weighted score of its
+// conventional type, keywords, referenced issue labels and touched test files
+type BugClassifier struct {
+	Prefixes  []string // case-insensitive subject prefixes, e.g. "bug:"
+	NonBug    []*regexp.Regexp
+	BugLabels []string // case-insensitive issue labels
+}
+
+// DefaultBugClassifier returns a classifier with the default prefixes, negative patterns and labels
+func DefaultBugClassifier() *BugClassifier {
+	classifier, err := NewBugClassifier(DefaultBugPrefixes, DefaultNonBugPatterns)
+	if err != nil {
+		panic(err)
+	}
+	return classifier
+}
+
+// NewBugClassifier compiles the negative patterns; bug labels default to issues.DefaultBugLabels
+func NewBugClassifier(prefixes []string, nonBugPatterns []string) (*BugClassifier, error) {
+	c := &BugClassifier{BugLabels: issues.DefaultBugLabels}
+	for _, prefix := range prefixes {
+		c.Prefixes = append(c.Prefixes, strings.ToLower(prefix))
+	}
+	for _, pattern := range nonBugPatterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid non-bug pattern %q: %w", pattern, err)
+		}
+		c.NonBug = append(c.NonBug, regex)
+	}
+	return c, nil
+}
+
+// NewBugClassifierFromConfig uses the configured BugPrefixes and NonBugPatterns, falling
+// back to the defaults for whichever is empty
+func NewBugClassifierFromConfig(config models.FeatureDetectionConfig) (*BugClassifier, error) {
+	prefixes := config.BugPrefixes
+	if len(prefixes) == 0 {
+		prefixes = DefaultBugPrefixes
+	}
+	nonBug := config.NonBugPatterns
+	if len(nonBug) == 0 {
+		nonBug = DefaultNonBugPatterns
+	}
+	return NewBugClassifier(prefixes, nonBug)
+}
+
+// Score classifies a commit from its message, changed files and issue references
+func (c *BugClassifier) Score(message string, files []string, refs []models.IssueRef) BugScore {
+	var score BugScore
+
+	subject, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	description := subject
+	bugType := false
+	if conventional := ParseConventionalCommit(message); conventional != nil {
+		description = conventional.Description
+		switch typ := strings.ToLower(conventional.Type); {
+		case bugTypes[typ]:
+			bugType = true
+			score.add(bugTypeWeight, "type "+typ)
+		case nonBugTypes[typ]:
+			score.add(nonBugTypeWeight, "type "+typ)
+		}
+	}
+
+	lowerSubject := strings.ToLower(subject)
+	for _, prefix := range c.Prefixes {
+		if !bugType && strings.HasPrefix(lowerSubject, prefix) {
+			score.add(bugTypeWeight, "prefix "+prefix)
+			description = strings.TrimSpace(subject[len(prefix):])
+			break
+		}
+	}
+
+	if keyword := bugKeywords.FindString(description); keyword != "" {
+		score.add(subjectKeywordWeight, "keyword "+strings.ToLower(keyword))
+	} else if keyword := bugKeywords.FindString(body); keyword != "" {
+		score.add(bodyKeywordWeight, "body keyword "+strings.ToLower(keyword))
+	}
+
+	for _, regex := range c.NonBug {
+		if regex.MatchString(subject) {
+			score.add(nonBugPatternWeight, "not a bug: "+regex.String())
+			break
+		}
+	}
+
+	if label := c.bugLabel(refs); label != "" {
+		score.add(bugLabelWeight, "issue label "+label)
+	}
+
+	for _, file := range files {
+		if testFile.MatchString(file) {
+			score.add(testFileWeight, "touches tests")
+			break
+		}
+	}
+
+	return score
+}
+
+func (c *BugClassifier) bugLabel(refs []models.IssueRef) string {
+	for _, ref := range refs {
+		for _, label := range ref.Labels {
+			for _, bugLabel := range c.BugLabels {
+				if strings.EqualFold(label, bugLabel) {
+					return label
+				}
+			}
+		}
+	}
+	return ""
+}
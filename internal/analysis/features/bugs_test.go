@@ -0,0 +1,159 @@
+package features
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/issues"
+	"git-history-onboarding/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// bugCorpus is a labeled sample of commit subjects and bodies from real-world repositories
+var bugCorpus = []struct {
+	message string
+	files   []string
+	bug     bool
+}{
+	{"fix: handle nil session in middleware", nil, true},
+	{"fix(cart): total ignores discounts", nil, true},
+	{"Fix crash when the config file is empty", nil, true},
+	{"Fixed a bug in authentication", nil, true},
+	{"Fixes #123: login loop on expired tokens", nil, true},
+	{"hotfix: restore checkout button", nil, true},
+	{"bug: search returns duplicates", nil, true},
+	{"[fix] off-by-one in pagination", nil, true},
+	{"Resolve regression in CSV export", nil, true},
+	{"Prevent panic on empty payload", nil, true},
+	{"fix!: reject malformed JWTs", nil, true},
+	{"Handle broken symlinks in scanner", nil, true},
+	{"Guard against nil map\n\nThis fixes a crash reported by users.", []string{"scanner/scan_test.go"}, true},
+	{"revert timeout change that caused a regression", nil, true},
+
+	{"Add prefix support to router", nil, false},
+	{"Add test fixtures for parser", []string{"parser/testdata/a.json"}, false},
+	{"Strip suffix from generated names", nil, false},
+	{"Update issue template", []string{".github/ISSUE_TEMPLATE/bug_report.md"}, false},
+	{"Add new feature", nil, false},
+	{"feat(auth): add sso", nil, false},
+	{"docs: describe how to report a bug", nil, false},
+	{"Fix typo in README", nil, false},
+	{"fix: typo in error message", nil, false},
+	{"chore: fix lint warnings", nil, false},
+	{"Fix tests on Windows", []string{"internal/git/files_test.go"}, false},
+	{"Merge branch 'fix/login' into main", nil, false},
+	{"ci: fix release workflow", nil, false},
+	{"style: fix indentation", nil, false},
+	{"Resolve merge conflicts", nil, false},
+	{"Patch version bump to 1.2.3", nil, false},
+	{"refactor: simplify issue parser", nil, false},
+}
+
+func TestBugClassifierCorpus(t *testing.T) {
+	classifier := DefaultBugClassifier()
+
+	var truePositives, falsePositives, falseNegatives int
+	for _, sample := range bugCorpus {
+		predicted := classifier.Score(sample.message, sample.files, nil).IsBug()
+		switch {
+		case predicted && sample.bug:
+			truePositives++
+		case predicted && !sample.bug:
+			falsePositives++
+			t.Logf("false positive: %q", sample.message)
+		case !predicted && sample.bug:
+			falseNegatives++
+			t.Logf("false negative: %q", sample.message)
+		}
+	}
+
+	precision := float64(truePositives) / float64(truePositives+falsePositives)
+	recall := float64(truePositives) / float64(truePositives+falseNegatives)
+	assert.GreaterOrEqual(t, precision, 0.95, "precision")
+	assert.GreaterOrEqual(t, recall, 0.9, "recall")
+}
+
+func TestBugClassifierSignals(t *testing.T) {
+	classifier := DefaultBugClassifier()
+
+	t.Run("Issue labeled as bug", func(t *testing.T) {
+		refs := []models.IssueRef{{Tracker: "github", ID: "12", Labels: []string{"Bug"}}}
+		score := classifier.Score("Handle empty totals (#12)", nil, refs)
+		assert.True(t, score.IsBug())
+		assert.Equal(t, []string{"issue label Bug"}, score.Signals)
+	})
+
+	t.Run("Body keyword needs tests", func(t *testing.T) {
+		message := "Refactor parser\n\nAlso fixes quoting"
+		assert.False(t, classifier.Score(message, []string{"parser/parse.go"}, nil).IsBug())
+		assert.True(t, classifier.Score(message, []string{"parser/parse_test.go"}, nil).IsBug())
+	})
+
+	t.Run("Conventional type and keyword", func(t *testing.T) {
+		score := classifier.Score("fix(api): crash on empty body", nil, nil)
+		assert.Equal(t, []string{"type fix", "keyword crash"}, score.Signals)
+		assert.InDelta(t, bugTypeWeight+subjectKeywordWeight, score.Score, 1e-9)
+	})
+}
+
+func TestNewBugClassifierFromConfig(t *testing.T) {
+	classifier, err := NewBugClassifierFromConfig(models.FeatureDetectionConfig{
+		BugPrefixes:    []string{"DEFECT:"},
+		NonBugPatterns: []string{`(?i)\bflaky\b`},
+	})
+	assert.NoError(t, err)
+	assert.True(t, classifier.Score("defect: wrong VAT rate", nil, nil).IsBug())
+	assert.False(t, classifier.Score("bug: wrong VAT rate", nil, nil).IsBug(), "configured prefixes replace the defaults")
+	assert.False(t, classifier.Score("Fix flaky checkout", nil, nil).IsBug())
+	assert.True(t, classifier.Score("Fix typo handling in search", nil, nil).IsBug(), "configured patterns replace the defaults")
+
+	_, err = NewBugClassifierFromConfig(models.FeatureDetectionConfig{NonBugPatterns: []string{`(`}})
+	assert.Error(t, err)
+}
+
+// labeledIssues serves every issue with the given labels, opened on issueCreated
+type labeledIssues []string
+
+var issueCreated = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func (l labeledIssues) Fetch(ctx context.Context, id string) (*issues.Issue, error) {
+	return &issues.Issue{Title: "Issue " + id, Labels: l, CreatedAt: issueCreated}, nil
+}
+
+func TestAnalyzeCommitsScoresIssueLabels(t *testing.T) {
+	now := time.Now()
+	commits := []git.CommitInfo{
+		createTestCommit("aaa111", "Revert \"auth: cache tokens\"\n\nThis reverts commit bbb222. See #7", "Jane Smith", "jane@example.com", now, []string{"auth/token.go"}),
+		createTestCommit("ccc333", "docs(auth): describe login, refs #7", "Jane Smith", "jane@example.com", now, []string{"auth/README.md"}),
+		createTestCommit("ddd444", "auth: handle empty tokens, closes #7", "John Doe", "john@example.com", now, []string{"auth/token.go"}),
+	}
+
+	analyzer := NewAnalyzer()
+	analyzer.IssueDetails = issues.NewEnricher(map[string]issues.Client{issues.TrackerGitHub: labeledIssues{"bug"}})
+	assert.NoError(t, analyzer.IssueDetails.Prefetch(context.Background(), analyzer.Issues, commits))
+
+	auth := analyzer.AnalyzeCommits(commits)["Authentication"]
+	if assert.Len(t, auth.Bugs, 1, "reverts and docs referencing a bug-labeled issue are not bugs") {
+		assert.Equal(t, commits[2].Commit.Hash.String(), auth.Bugs[0].CommitHash)
+		assert.Equal(t, "Issue 7", auth.Bugs[0].Issues[0].Title)
+		assert.Equal(t, issueCreated, auth.Bugs[0].ReportedAt, "reported when the linked issue was opened")
+	}
+}
+
+func TestAnalyzeCommitsWithoutBugClassifier(t *testing.T) {
+	now := time.Now()
+	commits := []git.CommitInfo{
+		createTestCommit("aaa111", "fix(auth): reject empty tokens", "Jane Smith", "jane@example.com", now, []string{"auth/token.go"}),
+		createTestCommit("bbb222", "auth: cache tokens", "Jane Smith", "jane@example.com", now, []string{"auth/token.go"}),
+	}
+
+	analyzer := NewAnalyzer()
+	analyzer.Bugs = nil
+	auth := analyzer.AnalyzeCommits(commits)["Authentication"]
+	if assert.Len(t, auth.Bugs, 1, "the default classifier is used") {
+		assert.Equal(t, commits[0].Commit.Hash.String(), auth.Bugs[0].CommitHash)
+	}
+	assert.True(t, analyzer.isBugFix("fix: crash on empty config"))
+}
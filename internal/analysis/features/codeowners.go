@@ -0,0 +1,134 @@
+package features
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/glob"
+)
+
+// CodeownersLocations are the paths GitHub and GitLab read CODEOWNERS from, in order
+var CodeownersLocations = []string{"CODEOWNERS", ".github/CODEOWNERS", ".gitlab/CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeownersRule is one line of a CODEOWNERS file
+type CodeownersRule struct {
+	Pattern *glob.Pattern
+	Owners  []string
+}
+
+// Feature names the feature owned by this rule after its owners
+func (r CodeownersRule) Feature() string {
+	return strings.Join(r.Owners, " ")
+}
+
+// ParseCodeowners parses CODEOWNERS content, skipping comments, section headers and
+// rules without owners
+func ParseCodeowners(data []byte) ([]CodeownersRule, error) {
+	var rules []CodeownersRule
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		pattern, err := glob.Compile(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("CODEOWNERS line %d: %w", lineNumber, err)
+		}
+		rules = append(rules, CodeownersRule{Pattern: pattern, Owners: fields[1:]})
+	}
+	return rules, scanner.Err()
+}
+
+// LoadCodeowners reads the first CODEOWNERS file found in src
+func LoadCodeowners(src FileSource) ([]CodeownersRule, error) {
+	files, err := src.ListFiles()
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[string]bool, len(files))
+	for _, file := range files {
+		present[file] = true
+	}
+
+	for _, location := range CodeownersLocations {
+		if !present[location] {
+			continue
+		}
+		data, err := src.ReadFile(location)
+		if err != nil {
+			return nil, err
+		}
+		return ParseCodeowners(data)
+	}
+	return nil, nil
+}
+
+// CodeownersDetector assigns commits to the owners of their files; as in CODEOWNERS,
+// the last matching rule wins
+type CodeownersDetector struct {
+	rules []CodeownersRule
+}
+
+func NewCodeownersDetector(rules []CodeownersRule) *CodeownersDetector {
+	return &CodeownersDetector{rules: rules}
+}
+
+func (d *CodeownersDetector) Name() string {
+	return "codeowners"
+}
+
+func (d *CodeownersDetector) Features() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, rule := range d.rules {
+		if !seen[rule.Feature()] {
+			seen[rule.Feature()] = true
+			names = append(names, rule.Feature())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (d *CodeownersDetector) Detect(commit git.CommitInfo) []FeatureMatch {
+	seen := make(map[string]bool)
+	var matches []FeatureMatch
+	for _, file := range commit.Files {
+		file = filepath.ToSlash(file)
+		for i := len(d.rules) - 1; i >= 0; i-- {
+			if !d.rules[i].Pattern.Match(file) {
+				continue
+			}
+			if feature := d.rules[i].Feature(); !seen[feature] {
+				seen[feature] = true
+				matches = append(matches, FeatureMatch{
+					Feature:    feature,
+					Confidence: codeownersConfidence,
+					Detector:   d.Name(),
+					Rule:       RuleFile,
+					Pattern:    d.rules[i].Pattern.String(),
+					Text:       file,
+				})
+			}
+			break
+		}
+	}
+	return matches
+}
@@ -0,0 +1,49 @@
+package features
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCodeowners(t *testing.T) {
+	rules, err := ParseCodeowners([]byte(`# Default owners
+*       @acme/core
+
+[Frontend]
+/web/   @acme/frontend @alice # UI team
+docs/
+*.sql   @acme/data
+`))
+	assert.NoError(t, err)
+	assert.Len(t, rules, 3)
+	assert.Equal(t, "@acme/frontend @alice", rules[1].Feature())
+	assert.Equal(t, "*.sql", rules[2].Pattern.String())
+}
+
+func TestCodeownersDetector(t *testing.T) {
+	rules, err := ParseCodeowners([]byte("*  @acme/core\n/web/  @acme/frontend\n*.sql  @acme/data\n"))
+	assert.NoError(t, err)
+	detector := NewCodeownersDetector(rules)
+
+	commit := createTestCommit("abc123", "update", "John Doe", "john@example.com", time.Now(),
+		[]string{"web/app.tsx", "db/schema.sql", "main.go"})
+
+	assert.Equal(t, map[string]float64{
+		"@acme/frontend": codeownersConfidence,
+		"@acme/data":     codeownersConfidence,
+		"@acme/core":     codeownersConfidence,
+	}, matchedFeatures(detector.Detect(commit)))
+	assert.Equal(t, []string{"@acme/core", "@acme/data", "@acme/frontend"}, detector.Features())
+}
+
+func TestLoadCodeowners(t *testing.T) {
+	rules, err := LoadCodeowners(fakeSource{".github/CODEOWNERS": "*.go @gophers\n"})
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+
+	rules, err = LoadCodeowners(fakeSource{"README.md": ""})
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+}
@@ -0,0 +1,224 @@
+package features
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"git-history-onboarding/internal/git"
+)
+
+// ConventionalCommit is a commit message following https://www.conventionalcommits.org/en/v1.0.0/
+type ConventionalCommit struct {
+	Type                string
+	Scope               string   // scope as written, e.g. "ui, auth"
+	Scopes              []string // scope split on commas, e.g. "ui" and "auth"
+	Description         string
+	Body                string
+	Footers             []Footer
+	Breaking            bool
+	BreakingDescription string // BREAKING CHANGE footer text, or the description for "!"
+}
+
+// Footer is a "Token: value" or "Token #value" trailer, e.g. "Reviewed-by: Z" or "Fixes #12"
+type Footer struct {
+	Token string
+	Value string
+}
+
+var (
+	conventionalHeader = regexp.MustCompile(`^(?P<type>[A-Za-z]+)(?:\((?P<scope>[^()\r\n]+)\))?(?P<breaking>!)?: (?P<description>\S.*)$`)
+	footerLine         = regexp.MustCompile(`^(BREAKING CHANGE|[\w-]+)(?:: | #)(.*)$`)
+
+	// Near misses, to explain why a header does not conform
+	headerNoSpace     = regexp.MustCompile(`^[A-Za-z]+(?:\([^()]*\))?!?:\S`)
+	headerEmptyScope  = regexp.MustCompile(`^[A-Za-z]+\(\s*\)`)
+	headerNoDesc      = regexp.MustCompile(`^[A-Za-z]+(?:\([^()]*\))?!?:\s*$`)
+	lowercaseBreaking = regexp.MustCompile(`(?im)^breaking[ -]change:`)
+)
+
+// ParseConventionalCommit parses a message per the Conventional Commits 1.0.0 spec, or
+// returns nil when its header does not conform. Footers are read from the last paragraph
+// when it holds only "Token: value" lines and their continuations; otherwise it stays in
+// the body. "BREAKING CHANGE" and "BREAKING-CHANGE" footers mark the commit
+// as breaking
+func ParseConventionalCommit(message string) *ConventionalCommit {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(message), "\r\n", "\n"), "\n")
+
+	matches := conventionalHeader.FindStringSubmatch(strings.TrimRight(lines[0], " \t"))
+	if matches == nil {
+		return nil
+	}
+
+	commit := &ConventionalCommit{
+		Type:        matches[conventionalHeader.SubexpIndex("type")],
+		Scope:       strings.TrimSpace(matches[conventionalHeader.SubexpIndex("scope")]),
+		Description: strings.TrimSpace(matches[conventionalHeader.SubexpIndex("description")]),
+		Breaking:    matches[conventionalHeader.SubexpIndex("breaking")] != "",
+	}
+	for _, scope := range strings.Split(commit.Scope, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			commit.Scopes = append(commit.Scopes, scope)
+		}
+	}
+
+	// The footers are the last paragraph, when every line in it is a footer or a
+	// continuation of one
+	rest := lines[1:]
+	footerStart := 0
+	for i, line := range rest {
+		if strings.TrimSpace(line) == "" {
+			footerStart = i + 1
+		}
+	}
+	if !isFooterBlock(rest[footerStart:]) {
+		footerStart = len(rest)
+	}
+
+	commit.Body = strings.TrimSpace(strings.Join(rest[:footerStart], "\n"))
+	for _, line := range rest[footerStart:] {
+		if m := footerLine.FindStringSubmatch(line); m != nil {
+			commit.Footers = append(commit.Footers, Footer{Token: m[1], Value: m[2]})
+			continue
+		}
+		last := &commit.Footers[len(commit.Footers)-1]
+		last.Value += "\n" + line
+	}
+
+	for i := range commit.Footers {
+		footer := &commit.Footers[i]
+		footer.Value = strings.TrimSpace(footer.Value)
+		if footer.IsBreaking() {
+			if !commit.Breaking || commit.BreakingDescription == "" {
+				commit.BreakingDescription = footer.Value
+			}
+			commit.Breaking = true
+		}
+	}
+	if commit.Breaking && commit.BreakingDescription == "" {
+		commit.BreakingDescription = commit.Description
+	}
+
+	return commit
+}
+
+// isFooterBlock reports whether a paragraph starts with a footer and every other line
+// is a footer or a continuation: indented, or any line of a BREAKING CHANGE
+// description, which often runs over several lines
+func isFooterBlock(lines []string) bool {
+	if len(lines) == 0 {
+		return false
+	}
+	var current *Footer
+	for _, line := range lines {
+		if m := footerLine.FindStringSubmatch(line); m != nil {
+			current = &Footer{Token: m[1]}
+			continue
+		}
+		indented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+		if current == nil || !(indented || current.IsBreaking()) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsBreaking reports whether the footer announces a breaking change
+func (f Footer) IsBreaking() bool {
+	return f.Token == "BREAKING CHANGE" || f.Token == "BREAKING-CHANGE"
+}
+
+// Footer returns the value of the first footer with the given token, compared
+// case-insensitively, and whether there is one
+func (c *ConventionalCommit) Footer(token string) (string, bool) {
+	for _, footer := range c.Footers {
+		if strings.EqualFold(footer.Token, token) {
+			return footer.Value, true
+		}
+	}
+	return "", false
+}
+
+// LintMessage returns the ways a message breaks the Conventional Commits spec, or nil
+// when it conforms
+func LintMessage(message string) []string {
+	message = strings.ReplaceAll(strings.TrimSpace(message), "\r\n", "\n")
+	lines := strings.Split(message, "\n")
+	header := strings.TrimRight(lines[0], " \t")
+
+	var problems []string
+	switch {
+	case conventionalHeader.MatchString(header):
+	case headerEmptyScope.MatchString(header):
+		problems = append(problems, "empty scope")
+	case headerNoDesc.MatchString(header):
+		problems = append(problems, "empty description")
+	case headerNoSpace.MatchString(header):
+		problems = append(problems, "missing space after the colon")
+	default:
+		problems = append(problems, `header is not "<type>[(scope)][!]: <description>"`)
+	}
+
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		problems = append(problems, "body must be separated from the header by a blank line")
+	}
+	for _, m := range lowercaseBreaking.FindAllString(message, -1) {
+		if m != "BREAKING CHANGE:" && m != "BREAKING-CHANGE:" {
+			problems = append(problems, "BREAKING CHANGE must be uppercase")
+			break
+		}
+	}
+	return problems
+}
+
+// AuthorLint counts an author's commits that do not follow the Conventional Commits spec
+type AuthorLint struct {
+	AuthorEmail   string
+	Commits       int
+	NonConforming int
+	Problems      map[string]int // problem -> commits with it
+}
+
+// Share returns the fraction of the author's commits that do not conform
+func (l AuthorLint) Share() float64 {
+	if l.Commits == 0 {
+		return 0
+	}
+	return float64(l.NonConforming) / float64(l.Commits)
+}
+
+// LintCommits lints every non-merge commit and returns per-author results, the least
+// conforming authors first
+func LintCommits(commits []git.CommitInfo) []AuthorLint {
+	byAuthor := make(map[string]*AuthorLint)
+	for _, commit := range commits {
+		if commit.Commit.NumParents() > 1 {
+			continue
+		}
+		email := commit.Commit.Author.Email
+		lint, ok := byAuthor[email]
+		if !ok {
+			lint = &AuthorLint{AuthorEmail: email, Problems: make(map[string]int)}
+			byAuthor[email] = lint
+		}
+		lint.Commits++
+		if problems := LintMessage(commit.Commit.Message); len(problems) > 0 {
+			lint.NonConforming++
+			for _, problem := range problems {
+				lint.Problems[problem]++
+			}
+		}
+	}
+
+	results := make([]AuthorLint, 0, len(byAuthor))
+	for _, lint := range byAuthor {
+		results = append(results, *lint)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Share() != results[j].Share() {
+			return results[i].Share() > results[j].Share()
+		}
+		return results[i].AuthorEmail < results[j].AuthorEmail
+	})
+	return results
+}
@@ -0,0 +1,238 @@
+package features
+
+import (
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConventionalCommitsConformance covers the examples and rules of the Conventional Commits 1.0.0 spec
+func TestConventionalCommitsConformance(t *testing.T) {
+	testCases := []struct {
+		name     string
+		message  string
+		expected *ConventionalCommit
+	}{
+		{
+			name:    "Description and breaking change footer",
+			message: "feat: allow provided config object to extend other configs\n\nBREAKING CHANGE: `extends` key in config file is now used for extending other config files",
+			expected: &ConventionalCommit{
+				Type:                "feat",
+				Description:         "allow provided config object to extend other configs",
+				Footers:             []Footer{{Token: "BREAKING CHANGE", Value: "`extends` key in config file is now used for extending other config files"}},
+				Breaking:            true,
+				BreakingDescription: "`extends` key in config file is now used for extending other config files",
+			},
+		},
+		{
+			name:    "! to draw attention to breaking change",
+			message: "feat!: send an email to the customer when a product is shipped",
+			expected: &ConventionalCommit{
+				Type:                "feat",
+				Description:         "send an email to the customer when a product is shipped",
+				Breaking:            true,
+				BreakingDescription: "send an email to the customer when a product is shipped",
+			},
+		},
+		{
+			name:    "Scope and ! to draw attention to breaking change",
+			message: "feat(api)!: send an email to the customer when a product is shipped",
+			expected: &ConventionalCommit{
+				Type:                "feat",
+				Scope:               "api",
+				Scopes:              []string{"api"},
+				Description:         "send an email to the customer when a product is shipped",
+				Breaking:            true,
+				BreakingDescription: "send an email to the customer when a product is shipped",
+			},
+		},
+		{
+			name:    "Both ! and BREAKING CHANGE footer",
+			message: "chore!: drop support for Node 6\n\nBREAKING CHANGE: use JavaScript features not available in Node 6.",
+			expected: &ConventionalCommit{
+				Type:                "chore",
+				Description:         "drop support for Node 6",
+				Footers:             []Footer{{Token: "BREAKING CHANGE", Value: "use JavaScript features not available in Node 6."}},
+				Breaking:            true,
+				BreakingDescription: "use JavaScript features not available in Node 6.",
+			},
+		},
+		{
+			name:     "No body",
+			message:  "docs: correct spelling of CHANGELOG",
+			expected: &ConventionalCommit{Type: "docs", Description: "correct spelling of CHANGELOG"},
+		},
+		{
+			name:    "Multi-paragraph body and multiple footers",
+			message: "fix: prevent racing of requests\n\nIntroduce a request id and a reference to latest request. Dismiss\nincoming responses other than from latest request.\n\nRemove timeouts which were used to mitigate the racing issue but are\nobsolete now.\n\nReviewed-by: Z\nRefs: #123",
+			expected: &ConventionalCommit{
+				Type:        "fix",
+				Description: "prevent racing of requests",
+				Body:        "Introduce a request id and a reference to latest request. Dismiss\nincoming responses other than from latest request.\n\nRemove timeouts which were used to mitigate the racing issue but are\nobsolete now.",
+				Footers:     []Footer{{Token: "Reviewed-by", Value: "Z"}, {Token: "Refs", Value: "#123"}},
+			},
+		},
+		{
+			name:    "Footer with # separator and multi-line value",
+			message: "fix(cart): round totals\n\nFixes #42\nSigned-off-by: Jane\nBREAKING-CHANGE: totals are now\n  rounded to cents",
+			expected: &ConventionalCommit{
+				Type:        "fix",
+				Scope:       "cart",
+				Scopes:      []string{"cart"},
+				Description: "round totals",
+				Footers: []Footer{
+					{Token: "Fixes", Value: "42"},
+					{Token: "Signed-off-by", Value: "Jane"},
+					{Token: "BREAKING-CHANGE", Value: "totals are now\n  rounded to cents"},
+				},
+				Breaking:            true,
+				BreakingDescription: "totals are now\n  rounded to cents",
+			},
+		},
+		{
+			name:    "Multiple scopes",
+			message: "fix(ui, auth): keep session on reload",
+			expected: &ConventionalCommit{
+				Type:        "fix",
+				Scope:       "ui, auth",
+				Scopes:      []string{"ui", "auth"},
+				Description: "keep session on reload",
+			},
+		},
+		{
+			name:    "Scope with slash",
+			message: "feat(api/v2): add pagination",
+			expected: &ConventionalCommit{
+				Type:        "feat",
+				Scope:       "api/v2",
+				Scopes:      []string{"api/v2"},
+				Description: "add pagination",
+			},
+		},
+		{
+			name:     "Types are case-insensitive",
+			message:  "FEAT: shout",
+			expected: &ConventionalCommit{Type: "FEAT", Description: "shout"},
+		},
+		{
+			name:    "Token-like body line not after a blank line",
+			message: "feat: add retries\n\nRetries happen on\nNote: timeouts only",
+			expected: &ConventionalCommit{
+				Type:        "feat",
+				Description: "add retries",
+				Body:        "Retries happen on\nNote: timeouts only",
+			},
+		},
+		{
+			name:    "Lowercase breaking change stays in the body",
+			message: "feat: x\n\nbreaking change: nope",
+			expected: &ConventionalCommit{
+				Type:        "feat",
+				Description: "x",
+				Body:        "breaking change: nope",
+			},
+		},
+		{
+			name:    "Token-like paragraph followed by more body",
+			message: "feat: add retries\n\nNote: timeouts only\n\nMore details...",
+			expected: &ConventionalCommit{
+				Type:        "feat",
+				Description: "add retries",
+				Body:        "Note: timeouts only\n\nMore details...",
+			},
+		},
+		{
+			name:    "Reference followed by an unindented line",
+			message: "fix: handle\n\nSee #12 for details\nand more",
+			expected: &ConventionalCommit{
+				Type:        "fix",
+				Description: "handle",
+				Body:        "See #12 for details\nand more",
+			},
+		},
+		{
+			name:    "Footers after a body paragraph",
+			message: "fix: handle\n\nSee #12 for details\nand more\n\nRefs: #12",
+			expected: &ConventionalCommit{
+				Type:        "fix",
+				Description: "handle",
+				Body:        "See #12 for details\nand more",
+				Footers:     []Footer{{Token: "Refs", Value: "#12"}},
+			},
+		},
+		{name: "Missing space after colon", message: "feat:add login"},
+		{name: "Empty scope", message: "feat(): add login"},
+		{name: "Empty description", message: "feat: "},
+		{name: "Unclosed scope", message: "feat(api: add login"},
+		{name: "Not conventional", message: "Add login"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ParseConventionalCommit(tc.message))
+		})
+	}
+}
+
+func TestConventionalCommitFooter(t *testing.T) {
+	commit := ParseConventionalCommit("fix: x\n\nReviewed-by: Z\nRefs: #123")
+	value, ok := commit.Footer("refs")
+	assert.True(t, ok)
+	assert.Equal(t, "#123", value)
+
+	_, ok = commit.Footer("Closes")
+	assert.False(t, ok)
+}
+
+func TestLintMessage(t *testing.T) {
+	testCases := []struct {
+		message  string
+		problems []string
+	}{
+		{"feat(api): add pagination\n\nBody\n\nBREAKING CHANGE: pages", nil},
+		{"feat:add login", []string{"missing space after the colon"}},
+		{"feat(): add login", []string{"empty scope"}},
+		{"fix:", []string{"empty description"}},
+		{"Add login", []string{`header is not "<type>[(scope)][!]: <description>"`}},
+		{"feat: add login\nmore text", []string{"body must be separated from the header by a blank line"}},
+		{"feat: add login\n\nBreaking change: sessions reset", []string{"BREAKING CHANGE must be uppercase"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.message, func(t *testing.T) {
+			assert.Equal(t, tc.problems, LintMessage(tc.message))
+		})
+	}
+}
+
+func TestLintCommits(t *testing.T) {
+	now := time.Now()
+	merge := createTestCommit("fff666", "Merge branch 'main'", "Jane Smith", "jane@example.com", now, nil)
+	merge.Commit.ParentHashes = []plumbing.Hash{plumbing.NewHash("aaa111"), plumbing.NewHash("bbb222")}
+
+	commits := []git.CommitInfo{
+		createTestCommit("aaa111", "feat(auth): add sso", "John Doe", "john@example.com", now, nil),
+		createTestCommit("bbb222", "fixed login", "John Doe", "john@example.com", now, nil),
+		createTestCommit("ccc333", "fix: session", "Jane Smith", "jane@example.com", now, nil),
+		createTestCommit("ddd444", "wip", "Bob", "bob@example.com", now, nil),
+		merge,
+	}
+
+	results := LintCommits(commits)
+	if assert.Len(t, results, 3) {
+		assert.Equal(t, "bob@example.com", results[0].AuthorEmail)
+		assert.Equal(t, 1.0, results[0].Share())
+
+		assert.Equal(t, "john@example.com", results[1].AuthorEmail)
+		assert.Equal(t, 2, results[1].Commits)
+		assert.Equal(t, 0.5, results[1].Share())
+		assert.Equal(t, map[string]int{`header is not "<type>[(scope)][!]: <description>"`: 1}, results[1].Problems)
+
+		assert.Equal(t, "jane@example.com", results[2].AuthorEmail)
+		assert.Equal(t, 1, results[2].Commits, "merge commits are not linted")
+		assert.Zero(t, results[2].Share())
+	}
+}
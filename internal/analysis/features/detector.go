@@ -0,0 +1,232 @@
+package features
+
+import (
+	"fmt"
+	"sort"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+)
+
+// Rules describing what part of a commit produced a match
+const (
+	RuleScope       = "scope"
+	RuleDescription = "description"
+	RuleBody        = "body"
+	RuleFile        = "file"
+)
+
+// FeatureMatch is a feature assigned to a commit by a detector, with the reason why
+type FeatureMatch struct {
+	Feature    string
+	Confidence float64 // 0 to 1
+	Detector   string  // filled in by the registry when empty
+	Rule       string  // what matched, e.g. RuleScope or RuleFile
+	Pattern    string  // the pattern or rule that matched
+	Text       string  // the commit text or file path it matched
+}
+
+// Assignment converts the match into the explanation recorded on the feature
+func (m FeatureMatch) Assignment() models.Assignment {
+	return models.Assignment{
+		Detector:   m.Detector,
+		Rule:       m.Rule,
+		Pattern:    m.Pattern,
+		Text:       m.Text,
+		Confidence: m.Confidence,
+	}
+}
+
+// FeatureDetector assigns features to commits
+type FeatureDetector interface {
+	Name() string
+	Detect(commit git.CommitInfo) []FeatureMatch
+}
+
+// FeatureLister is implemented by detectors that know their features up front,
+// so they are reported even when no commit matches them
+type FeatureLister interface {
+	Features() []string
+}
+
+// MergePolicy decides how matches from several detectors are combined
+type MergePolicy string
+
+const (
+	// MergeUnion keeps the matches of every detector, using the highest confidence per feature
+	MergeUnion MergePolicy = "union"
+	// MergeFirst keeps only the matches of the highest precedence detector that matched
+	MergeFirst MergePolicy = "first"
+	// MergeHighest keeps only the features matched with the highest confidence
+	MergeHighest MergePolicy = "highest"
+)
+
+type registryEntry struct {
+	detector   FeatureDetector
+	precedence int
+}
+
+// Registry composes feature detectors in precedence order
+type Registry struct {
+	Policy        MergePolicy
+	MinConfidence float64 // matches below this confidence are dropped
+	entries       []registryEntry
+}
+
+func NewRegistry(policy MergePolicy) *Registry {
+	if policy == "" {
+		policy = MergeUnion
+	}
+	return &Registry{Policy: policy}
+}
+
+// Register adds a detector; detectors with a higher precedence are consulted first
+func (r *Registry) Register(detector FeatureDetector, precedence int) {
+	r.entries = append(r.entries, registryEntry{detector: detector, precedence: precedence})
+	sort.SliceStable(r.entries, func(i, j int) bool {
+		return r.entries[i].precedence > r.entries[j].precedence
+	})
+}
+
+// Detectors returns the registered detectors in precedence order
+func (r *Registry) Detectors() []FeatureDetector {
+	detectors := make([]FeatureDetector, 0, len(r.entries))
+	for _, entry := range r.entries {
+		detectors = append(detectors, entry.detector)
+	}
+	return detectors
+}
+
+// Features returns the features known up front by the registered detectors
+func (r *Registry) Features() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, entry := range r.entries {
+		lister, ok := entry.detector.(FeatureLister)
+		if !ok {
+			continue
+		}
+		for _, name := range lister.Features() {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// Detect runs the detectors against a commit and merges their matches according to the policy
+func (r *Registry) Detect(commit git.CommitInfo) []FeatureMatch {
+	var merged []FeatureMatch
+	index := make(map[string]int)
+
+	for _, entry := range r.entries {
+		matches := entry.detector.Detect(commit)
+
+		found := false
+		for _, match := range matches {
+			if match.Confidence < r.MinConfidence {
+				continue
+			}
+			found = true
+			if match.Detector == "" {
+				match.Detector = entry.detector.Name()
+			}
+			if i, exists := index[match.Feature]; exists {
+				if match.Confidence > merged[i].Confidence {
+					merged[i] = match
+				}
+				continue
+			}
+			index[match.Feature] = len(merged)
+			merged = append(merged, match)
+		}
+
+		if found && r.Policy == MergeFirst {
+			break
+		}
+	}
+
+	if r.Policy == MergeHighest && len(merged) > 0 {
+		highest := merged[0].Confidence
+		for _, match := range merged {
+			if match.Confidence > highest {
+				highest = match.Confidence
+			}
+		}
+		best := merged[:0]
+		for _, match := range merged {
+			if match.Confidence == highest {
+				best = append(best, match)
+			}
+		}
+		merged = best
+	}
+
+	return merged
+}
+
+// NewRegistryFromConfig builds a registry from the detectors named in the config.
+// Known detectors are "patterns", "paths", "codeowners", "modules" and "directories";
+// "codeowners" and "modules" read the repository at HEAD through src
+func NewRegistryFromConfig(config models.FeatureDetectionConfig, src FileSource) (*Registry, error) {
+	registry := NewRegistry(MergePolicy(config.MergePolicy))
+	registry.MinConfidence = config.MinConfidence
+
+	switch registry.Policy {
+	case MergeUnion, MergeFirst, MergeHighest:
+	default:
+		return nil, fmt.Errorf("unknown merge policy: %s", config.MergePolicy)
+	}
+
+	detectors := config.Detectors
+	if len(detectors) == 0 {
+		detectors = []models.DetectorConfig{{Name: "patterns"}}
+	}
+
+	// A scope alias table applies ahead of the other detectors unless placed explicitly
+	if len(config.ScopeAliases) > 0 {
+		listed, top := false, 0
+		for i, dc := range detectors {
+			listed = listed || dc.Name == "scopes"
+			if i == 0 || dc.Precedence > top {
+				top = dc.Precedence
+			}
+		}
+		if !listed {
+			detectors = append(detectors, models.DetectorConfig{Name: "scopes", Precedence: top + 1})
+		}
+	}
+
+	for _, dc := range detectors {
+		var detector FeatureDetector
+		switch dc.Name {
+		case "patterns":
+			detector = NewRegexDetector(DefaultFeaturePatterns())
+		case "paths":
+			detector = NewPathDetector(config.FeaturePaths)
+		case "scopes":
+			detector = NewScopeAliasDetector(config.ScopeAliases)
+		case "codeowners":
+			rules, err := LoadCodeowners(src)
+			if err != nil {
+				return nil, err
+			}
+			detector = NewCodeownersDetector(rules)
+		case "modules":
+			modules, err := DetectModules(src)
+			if err != nil {
+				return nil, err
+			}
+			detector = NewModuleDetector(modules)
+		case "directories":
+			detector = NewDirectoryDetector(dc.Depth)
+		default:
+			return nil, fmt.Errorf("unknown feature detector: %s", dc.Name)
+		}
+		registry.Register(detector, dc.Precedence)
+	}
+
+	return registry, nil
+}
@@ -0,0 +1,142 @@
+package features
+
+import (
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticDetector returns the same matches for every commit
+type staticDetector struct {
+	name    string
+	matches []FeatureMatch
+}
+
+func (d staticDetector) Name() string {
+	return d.name
+}
+
+func (d staticDetector) Detect(commit git.CommitInfo) []FeatureMatch {
+	return d.matches
+}
+
+func matchedFeatures(matches []FeatureMatch) map[string]float64 {
+	result := make(map[string]float64)
+	for _, match := range matches {
+		result[match.Feature] = match.Confidence
+	}
+	return result
+}
+
+func TestRegistryMergePolicies(t *testing.T) {
+	commit := createTestCommit("abc123", "chore: update", "John Doe", "john@example.com", time.Now(), nil)
+	low := staticDetector{name: "low", matches: []FeatureMatch{{Feature: "Billing", Confidence: 0.4}, {Feature: "API", Confidence: 0.9}}}
+	high := staticDetector{name: "high", matches: []FeatureMatch{{Feature: "Billing", Confidence: 0.7}}}
+
+	t.Run("Union keeps highest confidence per feature", func(t *testing.T) {
+		registry := NewRegistry(MergeUnion)
+		registry.Register(low, 0)
+		registry.Register(high, 10)
+
+		assert.Equal(t, map[string]float64{"Billing": 0.7, "API": 0.9}, matchedFeatures(registry.Detect(commit)))
+	})
+
+	t.Run("First uses highest precedence detector only", func(t *testing.T) {
+		registry := NewRegistry(MergeFirst)
+		registry.Register(low, 0)
+		registry.Register(high, 10)
+
+		assert.Equal(t, map[string]float64{"Billing": 0.7}, matchedFeatures(registry.Detect(commit)))
+		assert.Equal(t, "high", registry.Detectors()[0].Name())
+	})
+
+	t.Run("First falls through detectors without matches", func(t *testing.T) {
+		registry := NewRegistry(MergeFirst)
+		registry.Register(low, 0)
+		registry.Register(staticDetector{name: "empty"}, 10)
+
+		assert.Len(t, registry.Detect(commit), 2)
+	})
+
+	t.Run("Highest keeps the most confident features", func(t *testing.T) {
+		registry := NewRegistry(MergeHighest)
+		registry.Register(low, 0)
+		registry.Register(high, 10)
+
+		assert.Equal(t, map[string]float64{"API": 0.9}, matchedFeatures(registry.Detect(commit)))
+	})
+
+	t.Run("Minimum confidence", func(t *testing.T) {
+		registry := NewRegistry(MergeUnion)
+		registry.MinConfidence = 0.5
+		registry.Register(low, 0)
+
+		assert.Equal(t, map[string]float64{"API": 0.9}, matchedFeatures(registry.Detect(commit)))
+	})
+}
+
+func TestAnalyzerWithCustomDetector(t *testing.T) {
+	registry := NewRegistry(MergeUnion)
+	registry.Register(staticDetector{name: "in-house", matches: []FeatureMatch{{Feature: "Checkout", Confidence: 1}}}, 0)
+	analyzer := NewAnalyzerWithRegistry(registry)
+
+	commits := []git.CommitInfo{
+		createTestCommit("abc123", "add cart", "John Doe", "john@example.com", time.Now(), []string{"cart.go"}),
+	}
+
+	features := analyzer.AnalyzeCommits(commits)
+	assert.Contains(t, features, "Checkout")
+	assert.Len(t, features["Checkout"].Commits, 1)
+	assert.Contains(t, features["Checkout"].Owners, "john@example.com")
+}
+
+func TestPathDetector(t *testing.T) {
+	detector := NewPathDetector(map[string]string{
+		"services/":         "Services",
+		"services/payments": "Payments",
+	})
+
+	commit := createTestCommit("abc123", "update", "John Doe", "john@example.com", time.Now(),
+		[]string{"services/payments/refund.go", "services/users/user.go", "README.md"})
+
+	assert.Equal(t, map[string]float64{"Payments": pathConfidence, "Services": pathConfidence},
+		matchedFeatures(detector.Detect(commit)))
+	assert.Equal(t, []string{"Payments", "Services"}, detector.Features())
+}
+
+func TestNewRegistryFromConfig(t *testing.T) {
+	src := fakeSource{
+		".github/CODEOWNERS": "*.md @docs-team\n",
+		"go.mod":             "module example.com/app\n",
+		"main.go":            "package main",
+	}
+
+	config := models.FeatureDetectionConfig{
+		FeaturePaths: map[string]string{"auth/": "Authentication"},
+		Detectors: []models.DetectorConfig{
+			{Name: "patterns", Precedence: 0},
+			{Name: "paths", Precedence: 20},
+			{Name: "codeowners", Precedence: 10},
+		},
+		MergePolicy: "first",
+	}
+
+	registry, err := NewRegistryFromConfig(config, src)
+	assert.NoError(t, err)
+	assert.Equal(t, MergeFirst, registry.Policy)
+
+	var names []string
+	for _, detector := range registry.Detectors() {
+		names = append(names, detector.Name())
+	}
+	assert.Equal(t, []string{"paths", "codeowners", "patterns"}, names)
+
+	_, err = NewRegistryFromConfig(models.FeatureDetectionConfig{Detectors: []models.DetectorConfig{{Name: "unknown"}}}, src)
+	assert.Error(t, err)
+
+	_, err = NewRegistryFromConfig(models.FeatureDetectionConfig{MergePolicy: "random"}, src)
+	assert.Error(t, err)
+}
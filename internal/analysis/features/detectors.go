@@ -0,0 +1,203 @@
+package features
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"git-history-onboarding/internal/git"
+)
+
+// Confidence assigned by the built-in detectors depending on what matched
+const (
+	scopeConfidence       = 0.9
+	descriptionConfidence = 0.7
+	bodyConfidence        = 0.5
+	fileConfidence        = 0.6
+	pathConfidence        = 0.9
+	codeownersConfidence  = 0.8
+)
+
+// DefaultFeaturePatterns returns the built-in feature name -> regex patterns table
+func DefaultFeaturePatterns() map[string][]string {
+	return map[string][]string{
+		"Authentication": {`auth`, `login`, `oauth`, `sign[ui][pn]`, `signout`},
+		"User Profile":   {`profile`, `user[-_]?(?:profile|settings|management|dashboard)?`, `account`},
+		"API":            {`api(?:[-_](?:gateway|client|server|docs|documentation))?`, `graphql`, `rest`},
+		"Database":       {`db`, `database`, `storage`, `sql`, `nosql`, `orm`, `migration`},
+		"UI":             {`ui`, `interface`, `component`, `theme`, `style`, `css`, `html`, `javascript`, `react`, `vue`, `angular`, `svelte`, `tailwind`, `bootstrap`},
+		"Tests":          {`test`, `spec`, `_test\.go$`},
+		"Security":       {`auth`, `security`, `authentication`, `authorization`, `encrypt(?:ion)?`, `hash(?:ing)?`, `password`, `token`, `jwt`, `api[-_](?:key|token|secret)`},
+		"Notifications":  {`notification`, `notifier`, `notify`, `alert`, `toast`, `snackbar`},
+		"Analytics":      {`analytics`, `tracking`, `telemetry`, `metrics`, `stats`, `logger`, `logging`},
+		"Cache":          {`cache`, `memcached`, `redis`, `caching`},
+		"Search":         {`search`, `indexing`, `fulltext`, `autocomplete`, `filter`, `sort`},
+		"Payment":        {`payment`, `billing`, `subscription`, `invoice`, `purchase`},
+		"Admin":          {`admin`, `dashboard`, `management`, `control`, `panel`},
+		"Monitoring":     {`monitor`, `observe`, `stats`, `metrics`, `logging`, `tracing`},
+		"Logging":        {`log`, `logger`, `logging`, `syslog`, `journald`},
+		"Configuration":  {`config`, `configuration`, `settings`, `properties`, `properties`},
+		"Scheduling":     {`schedule`, `scheduler`, `cron`, `job`, `task`},
+		"Caching":        {`cache`, `memcached`, `redis`, `caching`},
+		"Rate Limiting":  {`rate`, `limit`, `limiter`, `throttle`},
+		"Documentation":  {`docs`, `documentation`, `readme`, `changelog`, `release`, `upgrade`, `migration`},
+	}
+}
+
+// RegexDetector matches feature patterns against the conventional commit scope,
+// then the description and body, then the changed files
+type RegexDetector struct {
+	names    []string
+	patterns map[string][]*regexp.Regexp
+}
+
+func NewRegexDetector(patterns map[string][]string) *RegexDetector {
+	compiledPatterns := make(map[string][]*regexp.Regexp)
+	names := make([]string, 0, len(patterns))
+	for feature, patternList := range patterns {
+		names = append(names, feature)
+		compiledPatterns[feature] = make([]*regexp.Regexp, 0, len(patternList))
+		for _, pattern := range patternList {
+			regex := regexp.MustCompile(`(?i)` + pattern) // (?i) makes it case-insensitive
+			compiledPatterns[feature] = append(compiledPatterns[feature], regex)
+		}
+	}
+	sort.Strings(names)
+
+	return &RegexDetector{names: names, patterns: compiledPatterns}
+}
+
+func (d *RegexDetector) Name() string {
+	return "patterns"
+}
+
+func (d *RegexDetector) Features() []string {
+	return d.names
+}
+
+func (d *RegexDetector) Detect(commit git.CommitInfo) []FeatureMatch {
+	conventionalCommit := ParseConventionalCommit(commit.Commit.Message)
+
+	var matches []FeatureMatch
+	for _, featureName := range d.names {
+		if match, ok := d.detectFeature(commit, conventionalCommit, featureName); ok {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// detectFeature checks the scope, then the description and body, then the files
+func (d *RegexDetector) detectFeature(commit git.CommitInfo, conventionalCommit *ConventionalCommit, featureName string) (FeatureMatch, bool) {
+	patterns := d.patterns[featureName]
+	match := FeatureMatch{Feature: featureName, Detector: d.Name()}
+
+	// Check conventional commit scope if available
+	if conventionalCommit != nil {
+		for _, scope := range conventionalCommit.Scopes {
+			if pattern := matchingPattern(scope, patterns); pattern != nil {
+				return match.with(scopeConfidence, RuleScope, pattern, scope), true
+			}
+		}
+	}
+
+	// If no match found in scope, check the description and body
+	if conventionalCommit != nil {
+		if pattern := matchingPattern(conventionalCommit.Description, patterns); pattern != nil {
+			return match.with(descriptionConfidence, RuleDescription, pattern, conventionalCommit.Description), true
+		}
+		if pattern := matchingPattern(conventionalCommit.Body, patterns); pattern != nil {
+			return match.with(bodyConfidence, RuleBody, pattern, conventionalCommit.Body), true
+		}
+	}
+
+	// If still no match, check the files
+	for _, file := range commit.Files {
+		if pattern := matchingPattern(file, patterns); pattern != nil {
+			return match.with(fileConfidence, RuleFile, pattern, file), true
+		}
+	}
+
+	return match, false
+}
+
+func (m FeatureMatch) with(confidence float64, rule string, pattern *regexp.Regexp, text string) FeatureMatch {
+	m.Confidence = confidence
+	m.Rule = rule
+	m.Pattern = strings.TrimPrefix(pattern.String(), "(?i)")
+	m.Text = text
+	return m
+}
+
+func matchesFeature(file string, patterns []*regexp.Regexp) bool {
+	return matchingPattern(file, patterns) != nil
+}
+
+// matchingPattern returns the first pattern matching the text, or nil
+func matchingPattern(text string, patterns []*regexp.Regexp) *regexp.Regexp {
+	normalizedPath := filepath.ToSlash(text)
+	for _, pattern := range patterns {
+		if pattern.MatchString(normalizedPath) {
+			return pattern
+		}
+	}
+	return nil
+}
+
+// PathDetector maps directories to features, e.g. "auth/" -> "Authentication".
+// The deepest configured directory containing a file wins
+type PathDetector struct {
+	paths map[string]string
+}
+
+func NewPathDetector(paths map[string]string) *PathDetector {
+	normalized := make(map[string]string, len(paths))
+	for dir, feature := range paths {
+		normalized[strings.Trim(filepath.ToSlash(dir), "/")] = feature
+	}
+	return &PathDetector{paths: normalized}
+}
+
+func (d *PathDetector) Name() string {
+	return "paths"
+}
+
+func (d *PathDetector) Features() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, feature := range d.paths {
+		if !seen[feature] {
+			seen[feature] = true
+			names = append(names, feature)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (d *PathDetector) Detect(commit git.CommitInfo) []FeatureMatch {
+	seen := make(map[string]bool)
+	var matches []FeatureMatch
+	for _, file := range commit.Files {
+		file = filepath.ToSlash(file)
+		best, found := "", false
+		for dir := range d.paths {
+			if pathContains(dir, file) && (!found || len(dir) > len(best)) {
+				best, found = dir, true
+			}
+		}
+		if found && !seen[d.paths[best]] {
+			seen[d.paths[best]] = true
+			matches = append(matches, FeatureMatch{
+				Feature:    d.paths[best],
+				Confidence: pathConfidence,
+				Detector:   d.Name(),
+				Rule:       RuleFile,
+				Pattern:    best + "/",
+				Text:       file,
+			})
+		}
+	}
+	return matches
+}
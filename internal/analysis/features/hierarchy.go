@@ -0,0 +1,225 @@
+package features
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+)
+
+// HierarchySeparator splits a feature name into its ancestors, e.g. "Payments > Refunds"
+const HierarchySeparator = " > "
+
+// ParentName returns the parent implied by a hierarchical feature name, or ""
+func ParentName(name string) string {
+	i := strings.LastIndex(name, HierarchySeparator)
+	if i < 0 {
+		return ""
+	}
+	return name[:i]
+}
+
+// buildHierarchy links features to their parents, creating missing parents, and rolls
+// commits, bugs and assignments up from children to every ancestor. Parents come from
+// the explicit child -> parent table first, then from hierarchical names
+func buildHierarchy(features map[string]*models.Feature, parents map[string]string) {
+	parentOf := func(name string) string {
+		if parent, ok := parents[name]; ok {
+			return parent
+		}
+		return ParentName(name)
+	}
+
+	// Create missing ancestors and link every feature to its parent
+	names := make([]string, 0, len(features))
+	for name := range features {
+		names = append(names, name)
+	}
+	for len(names) > 0 {
+		name := names[0]
+		names = names[1:]
+
+		parent := parentOf(name)
+		if parent == "" || parent == name {
+			continue
+		}
+		if _, exists := features[parent]; !exists {
+			features[parent] = newFeature(parent)
+			names = append(names, parent)
+		}
+		features[name].Parent = parent
+	}
+
+	for _, feature := range features {
+		feature.Children = nil
+	}
+	sorted := make([]string, 0, len(features))
+	for name := range features {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		if parent := features[name].Parent; parent != "" {
+			features[parent].Children = append(features[parent].Children, name)
+		}
+	}
+
+	// Roll each feature's own data up to all of its ancestors
+	own := make(map[string]*models.Feature, len(features))
+	for name, feature := range features {
+		copied := *feature
+		own[name] = &copied
+	}
+	for _, name := range sorted {
+		visited := map[string]bool{name: true}
+		for parent := features[name].Parent; parent != "" && !visited[parent]; parent = features[parent].Parent {
+			visited[parent] = true
+			rollUp(features[parent], own[name])
+		}
+	}
+}
+
+// rollUp merges a descendant's commits, bugs, reverts, breaking changes and assignments
+// into an ancestor
+func rollUp(ancestor, descendant *models.Feature) {
+	seen := make(map[string]bool, len(ancestor.Commits))
+	for _, commit := range ancestor.Commits {
+		seen[commit.Commit.Hash.String()] = true
+	}
+	for _, commit := range descendant.Commits {
+		hash := commit.Commit.Hash.String()
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		ancestor.Commits = append(ancestor.Commits, commit)
+		if assignment, ok := descendant.Assignments[hash]; ok {
+			ancestor.Assignments[hash] = assignment
+		}
+		if refs, ok := descendant.IssueRefs[hash]; ok {
+			ancestor.IssueRefs[hash] = refs
+		}
+	}
+
+	seenBugs := make(map[string]bool, len(ancestor.Bugs))
+	for _, bug := range ancestor.Bugs {
+		seenBugs[bug.CommitHash] = true
+	}
+	for _, bug := range descendant.Bugs {
+		if !seenBugs[bug.CommitHash] {
+			seenBugs[bug.CommitHash] = true
+			ancestor.Bugs = append(ancestor.Bugs, bug)
+		}
+	}
+
+	seenReverts := make(map[string]bool, len(ancestor.Reverts))
+	for _, revert := range ancestor.Reverts {
+		seenReverts[revert.CommitHash] = true
+	}
+	for _, revert := range descendant.Reverts {
+		if !seenReverts[revert.CommitHash] {
+			seenReverts[revert.CommitHash] = true
+			ancestor.Reverts = append(ancestor.Reverts, revert)
+		}
+	}
+
+	seenBreaking := make(map[string]bool, len(ancestor.BreakingChanges))
+	for _, change := range ancestor.BreakingChanges {
+		seenBreaking[change.CommitHash] = true
+	}
+	for _, change := range descendant.BreakingChanges {
+		if !seenBreaking[change.CommitHash] {
+			seenBreaking[change.CommitHash] = true
+			ancestor.BreakingChanges = append(ancestor.BreakingChanges, change)
+		}
+	}
+
+	if !descendant.CreatedAt.IsZero() && (ancestor.CreatedAt.IsZero() || descendant.CreatedAt.Before(ancestor.CreatedAt)) {
+		ancestor.CreatedAt = descendant.CreatedAt
+	}
+	if descendant.LastUpdated.After(ancestor.LastUpdated) {
+		ancestor.LastUpdated = descendant.LastUpdated
+	}
+}
+
+// Depth returns how deep a feature sits in the hierarchy, 1 for top-level features
+func Depth(features map[string]*models.Feature, name string) int {
+	depth := 0
+	visited := make(map[string]bool)
+	for name != "" && !visited[name] {
+		visited[name] = true
+		depth++
+		feature, ok := features[name]
+		if !ok {
+			break
+		}
+		name = feature.Parent
+	}
+	return depth
+}
+
+// Collapse returns the features down to the given depth, whose data already includes
+// their sub-features; a depth of 0 keeps every level
+func Collapse(features map[string]*models.Feature, depth int) map[string]*models.Feature {
+	if depth <= 0 {
+		return features
+	}
+	collapsed := make(map[string]*models.Feature)
+	for name, feature := range features {
+		if Depth(features, name) <= depth {
+			collapsed[name] = feature
+		}
+	}
+	return collapsed
+}
+
+// DirectoryDetector names features after the leading directories of changed files,
+// e.g. with a depth of 2 "services/payments/refund.go" belongs to "services > payments"
+type DirectoryDetector struct {
+	depth int
+}
+
+func NewDirectoryDetector(depth int) *DirectoryDetector {
+	if depth <= 0 {
+		depth = 1
+	}
+	return &DirectoryDetector{depth: depth}
+}
+
+func (d *DirectoryDetector) Name() string {
+	return "directories"
+}
+
+func (d *DirectoryDetector) Detect(commit git.CommitInfo) []FeatureMatch {
+	seen := make(map[string]bool)
+	var matches []FeatureMatch
+	for _, file := range commit.Files {
+		file = filepath.ToSlash(file)
+		dir := path.Dir(file)
+		if dir == "." {
+			continue
+		}
+
+		parts := strings.Split(dir, "/")
+		if len(parts) > d.depth {
+			parts = parts[:d.depth]
+		}
+		feature := strings.Join(parts, HierarchySeparator)
+		if seen[feature] {
+			continue
+		}
+		seen[feature] = true
+		matches = append(matches, FeatureMatch{
+			Feature:    feature,
+			Confidence: pathConfidence,
+			Detector:   d.Name(),
+			Rule:       RuleFile,
+			Pattern:    strings.Join(parts, "/") + "/",
+			Text:       file,
+		})
+	}
+	return matches
+}
@@ -0,0 +1,93 @@
+package features
+
+import (
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHierarchy(t *testing.T) {
+	now := time.Now()
+	registry := NewRegistry(MergeUnion)
+	registry.Register(NewPathDetector(map[string]string{
+		"payments/":                  "Payments",
+		"payments/refunds/":          "Payments > Refunds",
+		"payments/refunds/disputes/": "Payments > Refunds > Disputes",
+		"payments/invoices/":         "Invoices",
+	}), 0)
+	analyzer := NewAnalyzerWithRegistry(registry)
+	analyzer.Parents = map[string]string{"Invoices": "Payments"}
+
+	commits := []git.CommitInfo{
+		createTestCommit("aaa111", "feat: disputes", "John Doe", "john@example.com", now.Add(-72*time.Hour),
+			[]string{"payments/refunds/disputes/open.go"}),
+		createTestCommit("bbb222", "fix: refund rounding", "Jane Smith", "jane@example.com", now.Add(-48*time.Hour),
+			[]string{"payments/refunds/refund.go"}),
+		createTestCommit("ccc333", "feat: pdf invoices", "Jane Smith", "jane@example.com", now.Add(-24*time.Hour),
+			[]string{"payments/invoices/pdf.go"}),
+		createTestCommit("ddd444", "fix: dispute and refund state", "Bob Wilson", "bob@example.com", now,
+			[]string{"payments/refunds/disputes/state.go", "payments/refunds/state.go"}),
+	}
+
+	features := analyzer.AnalyzeCommits(commits)
+
+	t.Run("Links", func(t *testing.T) {
+		assert.Equal(t, "", features["Payments"].Parent)
+		assert.Equal(t, []string{"Invoices", "Payments > Refunds"}, features["Payments"].Children)
+		assert.Equal(t, "Payments > Refunds", features["Payments > Refunds > Disputes"].Parent)
+		assert.Equal(t, "Payments", features["Invoices"].Parent)
+	})
+
+	t.Run("Roll up", func(t *testing.T) {
+		assert.Len(t, features["Payments > Refunds > Disputes"].Commits, 2)
+		assert.Len(t, features["Payments > Refunds"].Commits, 3, "shared commits are counted once")
+		assert.Len(t, features["Payments > Refunds"].Bugs, 2)
+		assert.Len(t, features["Payments"].Commits, 4)
+		assert.Len(t, features["Payments"].Bugs, 2)
+		assert.Equal(t, now.Add(-72*time.Hour), features["Payments"].CreatedAt)
+		assert.Equal(t, now, features["Payments"].LastUpdated)
+		assert.InDelta(t, 0.5, features["Payments"].Owners["jane@example.com"], 0.01)
+	})
+
+	t.Run("Collapse", func(t *testing.T) {
+		assert.Equal(t, 1, Depth(features, "Payments"))
+		assert.Equal(t, 3, Depth(features, "Payments > Refunds > Disputes"))
+
+		top := Collapse(features, 1)
+		assert.Len(t, top, 1)
+		assert.Contains(t, top, "Payments")
+
+		assert.Len(t, Collapse(features, 2), 3)
+		assert.Len(t, Collapse(features, 0), 4)
+	})
+}
+
+func TestHierarchyCreatesMissingParents(t *testing.T) {
+	registry := NewRegistry(MergeUnion)
+	registry.Register(NewDirectoryDetector(2), 0)
+	analyzer := NewAnalyzerWithRegistry(registry)
+
+	features := analyzer.AnalyzeCommits([]git.CommitInfo{
+		createTestCommit("abc123", "refunds", "John Doe", "john@example.com", time.Now(),
+			[]string{"services/payments/refund.go", "services/users/user.go", "main.go"}),
+	})
+
+	assert.Len(t, features, 3)
+	assert.Equal(t, []string{"services > payments", "services > users"}, features["services"].Children)
+	assert.Len(t, features["services"].Commits, 1)
+	assert.Equal(t, "services", ParentName("services > payments"))
+}
+
+func TestHierarchyCycle(t *testing.T) {
+	analyzer := NewAnalyzer()
+	analyzer.Parents = map[string]string{"Cache": "Caching", "Caching": "Cache"}
+
+	features := analyzer.AnalyzeCommits([]git.CommitInfo{
+		createTestCommit("abc123", "feat(cache): warm up", "John Doe", "john@example.com", time.Now(), nil),
+	})
+
+	assert.Len(t, features["Cache"].Commits, 1)
+	assert.Equal(t, 2, Depth(features, "Cache"))
+}
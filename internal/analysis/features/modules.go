@@ -0,0 +1,389 @@
+package features
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"git-history-onboarding/internal/git"
+)
+
+// ModuleKind identifies the ecosystem a module was detected from
+type ModuleKind string
+
+const (
+	ModuleGo     ModuleKind = "go"
+	ModuleNPM    ModuleKind = "npm"
+	ModuleMaven  ModuleKind = "maven"
+	ModulePython ModuleKind = "python"
+)
+
+// Module is a package or module declared by the repository's manifests
+type Module struct {
+	Name string
+	Path string // directory relative to the repository root, "" for the root
+	Kind ModuleKind
+}
+
+// FileSource gives read access to the files of a repository at HEAD
+type FileSource interface {
+	ListFiles() ([]string, error)
+	ReadFile(path string) ([]byte, error)
+}
+
+// DetectModules reads the manifests found in src and returns the modules they declare:
+// Go packages under each go.mod, package.json workspaces, pom.xml modules and
+// Python packages containing an __init__.py
+func DetectModules(src FileSource) ([]Module, error) {
+	files, err := src.ListFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	fileSet := make(map[string]bool, len(files))
+	for _, file := range files {
+		fileSet[file] = true
+	}
+
+	var modules []Module
+	for _, detect := range []func(FileSource, []string, map[string]bool) ([]Module, error){
+		detectGoModules,
+		detectNPMModules,
+		detectMavenModules,
+		detectPythonModules,
+	} {
+		found, err := detect(src, files, fileSet)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, found...)
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		if modules[i].Path != modules[j].Path {
+			return modules[i].Path < modules[j].Path
+		}
+		return modules[i].Kind < modules[j].Kind
+	})
+
+	return modules, nil
+}
+
+// ModuleDetector assigns each commit to the modules containing its files
+type ModuleDetector struct {
+	modules []Module
+}
+
+func NewModuleDetector(modules []Module) *ModuleDetector {
+	return &ModuleDetector{modules: modules}
+}
+
+func (d *ModuleDetector) Name() string {
+	return "modules"
+}
+
+func (d *ModuleDetector) Features() []string {
+	names := make([]string, 0, len(d.modules))
+	for _, module := range d.modules {
+		names = append(names, module.Name)
+	}
+	return names
+}
+
+func (d *ModuleDetector) Detect(commit git.CommitInfo) []FeatureMatch {
+	seen := make(map[string]bool)
+	var matches []FeatureMatch
+	for _, file := range commit.Files {
+		module, ok := moduleForFile(d.modules, filepath.ToSlash(file))
+		if ok && !seen[module.Name] {
+			seen[module.Name] = true
+			matches = append(matches, FeatureMatch{
+				Feature:    module.Name,
+				Confidence: pathConfidence,
+				Detector:   d.Name(),
+				Rule:       RuleFile,
+				Pattern:    module.pattern(),
+				Text:       file,
+			})
+		}
+	}
+	return matches
+}
+
+// moduleForFile returns the module with the deepest path containing file. A Go package
+// holds only the files of its own directory, not its subdirectories
+func moduleForFile(modules []Module, file string) (Module, bool) {
+	var best Module
+	found := false
+	for _, module := range modules {
+		if module.Kind == ModuleGo && dirOf(file) != module.Path {
+			continue
+		}
+		if !pathContains(module.Path, file) {
+			continue
+		}
+		if !found || len(module.Path) > len(best.Path) {
+			best = module
+			found = true
+		}
+	}
+	return best, found
+}
+
+// pattern describes the files of a module in match explanations; the root module
+// matches everything
+func (m Module) pattern() string {
+	if m.Path == "" {
+		return "**"
+	}
+	return m.Path + "/"
+}
+
+func pathContains(dir, file string) bool {
+	return dir == "" || file == dir || strings.HasPrefix(file, dir+"/")
+}
+
+func dirOf(file string) string {
+	dir := path.Dir(file)
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+func isIgnoredDir(file string, names ...string) bool {
+	for _, part := range strings.Split(file, "/") {
+		for _, name := range names {
+			if part == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func detectGoModules(src FileSource, files []string, fileSet map[string]bool) ([]Module, error) {
+	// Module roots keyed by directory
+	roots := make(map[string]string)
+	for _, file := range files {
+		if path.Base(file) != "go.mod" || isIgnoredDir(file, "vendor", "testdata") {
+			continue
+		}
+		data, err := src.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if modulePath := parseGoModulePath(data); modulePath != "" {
+			roots[dirOf(file)] = modulePath
+		}
+	}
+	if len(roots) == 0 {
+		return nil, nil
+	}
+
+	packages := make(map[string]bool)
+	for _, file := range files {
+		if strings.HasSuffix(file, ".go") && !isIgnoredDir(file, "vendor", "testdata") {
+			packages[dirOf(file)] = true
+		}
+	}
+
+	var modules []Module
+	for dir := range packages {
+		// Find the nearest enclosing go.mod
+		root, modulePath, found := "", "", false
+		for rootDir, mp := range roots {
+			if pathContains(rootDir, dir) && (!found || len(rootDir) > len(root)) {
+				root, modulePath, found = rootDir, mp, true
+			}
+		}
+		if !found {
+			continue
+		}
+
+		name := modulePath
+		if rel := strings.TrimPrefix(strings.TrimPrefix(dir, root), "/"); rel != "" {
+			name = modulePath + "/" + rel
+		}
+		modules = append(modules, Module{Name: name, Path: dir, Kind: ModuleGo})
+	}
+
+	return modules, nil
+}
+
+func parseGoModulePath(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+		}
+	}
+	return ""
+}
+
+type packageJSON struct {
+	Name       string          `json:"name"`
+	Workspaces json.RawMessage `json:"workspaces"`
+}
+
+// workspacePatterns handles both the array form and the {"packages": [...]} form
+func (p packageJSON) workspacePatterns() []string {
+	if len(p.Workspaces) == 0 {
+		return nil
+	}
+	var patterns []string
+	if err := json.Unmarshal(p.Workspaces, &patterns); err == nil {
+		return patterns
+	}
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(p.Workspaces, &object); err == nil {
+		return object.Packages
+	}
+	return nil
+}
+
+func detectNPMModules(src FileSource, files []string, fileSet map[string]bool) ([]Module, error) {
+	if !fileSet["package.json"] {
+		return nil, nil
+	}
+
+	data, err := src.ReadFile("package.json")
+	if err != nil {
+		return nil, err
+	}
+	var root packageJSON
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, nil // Not a valid manifest, nothing to detect
+	}
+
+	modules := []Module{{Name: nameOr(root.Name, rootName(ModuleNPM)), Path: "", Kind: ModuleNPM}}
+
+	patterns := root.workspacePatterns()
+	for _, file := range files {
+		if path.Base(file) != "package.json" || file == "package.json" || isIgnoredDir(file, "node_modules") {
+			continue
+		}
+		dir := dirOf(file)
+		if !matchesWorkspace(dir, patterns) {
+			continue
+		}
+
+		data, err := src.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var pkg packageJSON
+		if err := json.Unmarshal(data, &pkg); err != nil {
+			continue
+		}
+		modules = append(modules, Module{Name: nameOr(pkg.Name, dir), Path: dir, Kind: ModuleNPM})
+	}
+
+	return modules, nil
+}
+
+func matchesWorkspace(dir string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+		if strings.HasSuffix(pattern, "/**") {
+			if pathContains(strings.TrimSuffix(pattern, "/**"), dir) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, dir); ok {
+			return true
+		}
+	}
+	return false
+}
+
+type pomXML struct {
+	ArtifactID string   `xml:"artifactId"`
+	Modules    []string `xml:"modules>module"`
+}
+
+func detectMavenModules(src FileSource, files []string, fileSet map[string]bool) ([]Module, error) {
+	if !fileSet["pom.xml"] {
+		return nil, nil
+	}
+
+	var modules []Module
+	seen := make(map[string]bool)
+
+	var visit func(dir string) error
+	visit = func(dir string) error {
+		manifest := path.Join(dir, "pom.xml")
+		if seen[dir] || !fileSet[manifest] {
+			return nil
+		}
+		seen[dir] = true
+
+		data, err := src.ReadFile(manifest)
+		if err != nil {
+			return err
+		}
+		var pom pomXML
+		if err := xml.Unmarshal(data, &pom); err != nil {
+			return nil
+		}
+
+		modules = append(modules, Module{Name: nameOr(pom.ArtifactID, nameOr(dir, rootName(ModuleMaven))), Path: dir, Kind: ModuleMaven})
+		for _, child := range pom.Modules {
+			childDir := path.Clean(path.Join(dir, strings.TrimSpace(child)))
+			if childDir == "." {
+				continue
+			}
+			if err := visit(childDir); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := visit(""); err != nil {
+		return nil, err
+	}
+	return modules, nil
+}
+
+func detectPythonModules(src FileSource, files []string, fileSet map[string]bool) ([]Module, error) {
+	var modules []Module
+	for _, file := range files {
+		if path.Base(file) != "__init__.py" {
+			continue
+		}
+		dir := dirOf(file)
+		if dir == "" || isIgnoredDir(file, "site-packages", ".venv", "venv") {
+			continue
+		}
+
+		// Walk up while the parent is still a package to build the dotted name
+		parts := strings.Split(dir, "/")
+		start := len(parts) - 1
+		for start > 0 && fileSet[path.Join(path.Join(parts[:start]...), "__init__.py")] {
+			start--
+		}
+
+		modules = append(modules, Module{Name: strings.Join(parts[start:], "."), Path: dir, Kind: ModulePython})
+	}
+	return modules, nil
+}
+
+// rootName names an unnamed root module after its ecosystem, e.g. "npm:root", so the
+// roots of a polyglot repository stay apart
+func rootName(kind ModuleKind) string {
+	return string(kind) + ":root"
+}
+
+func nameOr(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
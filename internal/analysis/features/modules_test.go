@@ -0,0 +1,201 @@
+package features
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSource serves files from memory
+type fakeSource map[string]string
+
+func (f fakeSource) ListFiles() ([]string, error) {
+	files := make([]string, 0, len(f))
+	for name := range f {
+		files = append(files, name)
+	}
+	return files, nil
+}
+
+func (f fakeSource) ReadFile(path string) ([]byte, error) {
+	contents, ok := f[path]
+	if !ok {
+		return nil, fmt.Errorf("file not found: %s", path)
+	}
+	return []byte(contents), nil
+}
+
+func moduleNames(modules []Module) map[string]string {
+	names := make(map[string]string)
+	for _, module := range modules {
+		names[module.Path] = module.Name
+	}
+	return names
+}
+
+func TestDetectModules(t *testing.T) {
+	t.Run("Go packages", func(t *testing.T) {
+		src := fakeSource{
+			"go.mod":                     "module example.com/app\n\ngo 1.21\n",
+			"main.go":                    "package main",
+			"internal/git/clone.go":      "package git",
+			"internal/git/clone_test.go": "package git",
+			"vendor/x/y.go":              "package y",
+			"tools/go.mod":               "module example.com/tools\n",
+			"tools/gen/main.go":          "package main",
+		}
+
+		modules, err := DetectModules(src)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"":             "example.com/app",
+			"internal/git": "example.com/app/internal/git",
+			"tools/gen":    "example.com/tools/gen",
+		}, moduleNames(modules))
+	})
+
+	t.Run("package.json workspaces", func(t *testing.T) {
+		src := fakeSource{
+			"package.json":                   `{"name": "monorepo", "workspaces": ["packages/*"]}`,
+			"packages/web/package.json":      `{"name": "@acme/web"}`,
+			"packages/api/package.json":      `{"name": "@acme/api"}`,
+			"node_modules/left/package.json": `{"name": "left"}`,
+			"docs/package.json":              `{"name": "docs"}`,
+		}
+
+		modules, err := DetectModules(src)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"":             "monorepo",
+			"packages/web": "@acme/web",
+			"packages/api": "@acme/api",
+		}, moduleNames(modules))
+	})
+
+	t.Run("Yarn workspaces object form", func(t *testing.T) {
+		src := fakeSource{
+			"package.json":           `{"name": "root", "workspaces": {"packages": ["apps/**"]}}`,
+			"apps/site/package.json": `{"name": "site"}`,
+		}
+
+		modules, err := DetectModules(src)
+		assert.NoError(t, err)
+		assert.Equal(t, "site", moduleNames(modules)["apps/site"])
+	})
+
+	t.Run("Maven modules", func(t *testing.T) {
+		src := fakeSource{
+			"pom.xml":        `<project><artifactId>parent</artifactId><modules><module>core</module><module>web</module></modules></project>`,
+			"core/pom.xml":   `<project><artifactId>acme-core</artifactId></project>`,
+			"web/pom.xml":    `<project><artifactId>acme-web</artifactId><modules><module>ui</module></modules></project>`,
+			"web/ui/pom.xml": `<project><artifactId>acme-ui</artifactId></project>`,
+			"orphan/pom.xml": `<project><artifactId>orphan</artifactId></project>`,
+		}
+
+		modules, err := DetectModules(src)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"":       "parent",
+			"core":   "acme-core",
+			"web":    "acme-web",
+			"web/ui": "acme-ui",
+		}, moduleNames(modules))
+	})
+
+	t.Run("Unnamed roots of several ecosystems", func(t *testing.T) {
+		src := fakeSource{
+			"package.json": `{"private": true}`,
+			"pom.xml":      `<project><modules><module>core</module></modules></project>`,
+			"core/pom.xml": `<project><artifactId>acme-core</artifactId></project>`,
+		}
+
+		modules, err := DetectModules(src)
+		assert.NoError(t, err)
+		var names []string
+		for _, module := range modules {
+			names = append(names, module.Name)
+		}
+		assert.ElementsMatch(t, []string{"npm:root", "maven:root", "acme-core"}, names)
+	})
+
+	t.Run("Python packages", func(t *testing.T) {
+		src := fakeSource{
+			"setup.py":                     "",
+			"src/acme/__init__.py":         "",
+			"src/acme/billing/__init__.py": "",
+			"src/acme/billing/refunds.py":  "",
+			"scripts/run.py":               "",
+		}
+
+		modules, err := DetectModules(src)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"src/acme":         "acme",
+			"src/acme/billing": "acme.billing",
+		}, moduleNames(modules))
+	})
+}
+
+func TestModuleForFile(t *testing.T) {
+	modules := []Module{
+		{Name: "example.com/app", Path: "", Kind: ModuleGo},
+		{Name: "example.com/app/internal/git", Path: "internal/git", Kind: ModuleGo},
+		{Name: "@acme/web", Path: "web", Kind: ModuleNPM},
+	}
+
+	for file, expected := range map[string]string{
+		"main.go":               "example.com/app",
+		"README.md":             "example.com/app",
+		"docs/guide.md":         "",
+		"internal/README.md":    "",
+		"internal/git/clone.go": "example.com/app/internal/git",
+		"web/src/app/index.ts":  "@acme/web",
+		"web/package.json":      "@acme/web",
+	} {
+		module, ok := moduleForFile(modules, file)
+		assert.Equal(t, expected != "", ok, file)
+		assert.Equal(t, expected, module.Name, file)
+	}
+}
+
+func TestModuleAnalyzer(t *testing.T) {
+	now := time.Now()
+	modules := []Module{
+		{Name: "example.com/app", Path: "", Kind: ModuleGo},
+		{Name: "example.com/app/internal/git", Path: "internal/git", Kind: ModuleGo},
+		{Name: "example.com/app/internal/models", Path: "internal/models", Kind: ModuleGo},
+	}
+	analyzer := NewModuleAnalyzer(modules)
+
+	commits := []git.CommitInfo{
+		createTestCommit("abc123", "add clone", "John Doe", "john@example.com", now,
+			[]string{"internal/git/clone.go", "internal/git/clone_test.go"}),
+		createTestCommit("def456", "fix: model bug", "Jane Smith", "jane@example.com", now,
+			[]string{"internal/models/feature.go", "main.go"}),
+	}
+
+	features := analyzer.AnalyzeCommits(commits)
+	assert.Len(t, features, 3)
+	assert.Len(t, features["example.com/app/internal/git"].Commits, 1)
+	assert.Len(t, features["example.com/app/internal/models"].Commits, 1)
+	assert.Len(t, features["example.com/app/internal/models"].Bugs, 1)
+	assert.Len(t, features["example.com/app"].Commits, 1)
+}
+
+func TestModuleDetectorPatterns(t *testing.T) {
+	detector := NewModuleDetector([]Module{
+		{Name: "npm:root", Path: "", Kind: ModuleNPM},
+		{Name: "@acme/web", Path: "web", Kind: ModuleNPM},
+	})
+	commit := createTestCommit("abc123", "update", "John Doe", "john@example.com", time.Now(),
+		[]string{"README.md", "web/src/index.ts"})
+
+	patterns := make(map[string]string)
+	for _, match := range detector.Detect(commit) {
+		patterns[match.Feature] = match.Pattern
+	}
+	assert.Equal(t, map[string]string{"npm:root": "**", "@acme/web": "web/"}, patterns)
+}
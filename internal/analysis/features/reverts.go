@@ -0,0 +1,168 @@
+package features
+
+import (
+	"regexp"
+	"strings"
+
+	"git-history-onboarding/internal/display"
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+)
+
+var (
+	revertSubject = regexp.MustCompile(`^Revert "(.*)"\s*$`)
+	revertBody    = regexp.MustCompile(`(?i)\bThis reverts commit ([0-9a-f]{7,40})\b`)
+)
+
+// ParseRevert recognizes the messages git revert writes, e.g. `Revert "feat: add sso"`
+// followed by "This reverts commit <sha>". It returns the hash as written and the
+// reverted subject, either of which may be empty, and ok is false for other commits
+func ParseRevert(message string) (hash, subject string, ok bool) {
+	line, _, _ := strings.Cut(message, "\n")
+	if matches := revertSubject.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+		subject, ok = matches[1], true
+	}
+	if matches := revertBody.FindStringSubmatch(message); matches != nil {
+		hash, ok = strings.ToLower(matches[1]), true
+	}
+	return hash, subject, ok
+}
+
+// revertIndex links the reverts in a history to the commits they undid
+type revertIndex struct {
+	reverts   map[string]models.Revert // revert commit hash -> revert
+	cancelled map[string]bool          // commits left out when reverts cancel out
+}
+
+// indexReverts resolves what every revert in the history undid, by full or abbreviated
+// hash and otherwise by subject. When cancel is set, each effective revert and the
+// commit it reverted are marked as cancelled; a reverted revert restores the original
+func indexReverts(commits []git.CommitInfo, cancel bool) revertIndex {
+	index := revertIndex{
+		reverts:   make(map[string]models.Revert),
+		cancelled: make(map[string]bool),
+	}
+
+	hashes := make([]string, 0, len(commits))
+	subjects := make(map[string]string, len(commits))
+	for _, commit := range commits {
+		hash := commit.Commit.Hash.String()
+		hashes = append(hashes, hash)
+		subjects[hash] = display.FirstLine(commit.Commit.Message)
+	}
+
+	revertedBy := make(map[string][]string)
+	for _, commit := range commits {
+		hash, subject, ok := ParseRevert(commit.Commit.Message)
+		if !ok {
+			continue
+		}
+
+		reverted := resolveRevert(hash, subject, hashes, subjects)
+		if reverted == "" {
+			reverted = hash
+		}
+		if description, ok := subjects[reverted]; ok {
+			subject = description
+		}
+
+		revertHash := commit.Commit.Hash.String()
+		index.reverts[revertHash] = models.Revert{
+			CommitHash:   revertHash,
+			RevertedHash: reverted,
+			Description:  subject,
+			AuthorEmail:  commit.Commit.Author.Email,
+			Date:         commit.Commit.Author.When,
+		}
+		if _, known := subjects[reverted]; known {
+			revertedBy[reverted] = append(revertedBy[reverted], revertHash)
+		}
+	}
+
+	if !cancel {
+		return index
+	}
+
+	// A commit is undone when some revert of it is not undone itself
+	undone := make(map[string]bool)
+	var isUndone func(hash string, visiting map[string]bool) bool
+	isUndone = func(hash string, visiting map[string]bool) bool {
+		if result, ok := undone[hash]; ok {
+			return result
+		}
+		if visiting[hash] {
+			return false
+		}
+		visiting[hash] = true
+		result := false
+		for _, revert := range revertedBy[hash] {
+			if !isUndone(revert, visiting) {
+				result = true
+				break
+			}
+		}
+		undone[hash] = result
+		return result
+	}
+
+	for revertHash, revert := range index.reverts {
+		if _, known := subjects[revert.RevertedHash]; !known || isUndone(revertHash, map[string]bool{}) {
+			continue
+		}
+		index.cancelled[revertHash] = true
+		index.cancelled[revert.RevertedHash] = true
+	}
+	for hash := range index.cancelled {
+		if revert, ok := index.reverts[hash]; ok {
+			revert.Cancelled = true
+			index.reverts[hash] = revert
+		}
+	}
+	return index
+}
+
+// resolveRevert finds the full hash of a reverted commit, or "" when it is not in the history
+func resolveRevert(hash, subject string, hashes []string, subjects map[string]string) string {
+	if hash != "" {
+		for _, candidate := range hashes {
+			if strings.HasPrefix(candidate, hash) {
+				return candidate
+			}
+		}
+		return ""
+	}
+	if subject != "" {
+		for _, candidate := range hashes {
+			if subjects[candidate] == subject {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+// RevertRate returns reverts per original change of a feature, a stability signal.
+// Cancelled commits still count, so the rate does not depend on Analyzer.CancelReverts
+func RevertRate(feature *models.Feature) float64 {
+	reverts := make(map[string]bool, len(feature.Reverts))
+	for _, revert := range feature.Reverts {
+		reverts[revert.CommitHash] = true
+	}
+
+	changes := make(map[string]bool)
+	for _, commit := range feature.Commits {
+		if hash := commit.Commit.Hash.String(); !reverts[hash] {
+			changes[hash] = true
+		}
+	}
+	for _, revert := range feature.Reverts {
+		if revert.Cancelled && !reverts[revert.RevertedHash] {
+			changes[revert.RevertedHash] = true
+		}
+	}
+
+	if len(changes) == 0 {
+		return 0
+	}
+	return float64(len(feature.Reverts)) / float64(len(changes))
+}
@@ -0,0 +1,109 @@
+package features
+
+import (
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRevert(t *testing.T) {
+	testCases := []struct {
+		name    string
+		message string
+		hash    string
+		subject string
+		ok      bool
+	}{
+		{
+			name:    "git revert message",
+			message: "Revert \"feat(auth): add sso\"\n\nThis reverts commit 0123456789abcdef0123456789abcdef01234567.",
+			hash:    "0123456789abcdef0123456789abcdef01234567",
+			subject: "feat(auth): add sso",
+			ok:      true,
+		},
+		{
+			name:    "Abbreviated hash only",
+			message: "Back out session cache\n\nThis reverts commit ABC1234.",
+			hash:    "abc1234",
+			ok:      true,
+		},
+		{
+			name:    "Subject only",
+			message: "Revert \"fix: retry payments\"",
+			subject: "fix: retry payments",
+			ok:      true,
+		},
+		{
+			name:    "Mentions reverting",
+			message: "fix: revert button color",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hash, subject, ok := ParseRevert(tc.message)
+			assert.Equal(t, tc.hash, hash)
+			assert.Equal(t, tc.subject, subject)
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+}
+
+func revertHistory(now time.Time) []git.CommitInfo {
+	sso := createTestCommit("a1", "feat(auth): add sso", "John Doe", "john@example.com", now.Add(-4*time.Hour), []string{"auth/sso.go"})
+	login := createTestCommit("b2", "feat(auth): remember login", "Jane Smith", "jane@example.com", now.Add(-3*time.Hour), []string{"auth/session.go"})
+	revertSSO := createTestCommit("c3", "Revert \"feat(auth): add sso\"\n\nThis reverts commit "+sso.Commit.Hash.String()[:7]+".",
+		"Jane Smith", "jane@example.com", now.Add(-2*time.Hour), []string{"auth/sso.go"})
+	revertLogin := createTestCommit("d4", "Revert \"feat(auth): remember login\"", "John Doe", "john@example.com", now.Add(-90*time.Minute), []string{"auth/session.go"})
+	reapplyLogin := createTestCommit("e5", "Revert \"Revert \\\"feat(auth): remember login\\\"\"\n\nThis reverts commit "+revertLogin.Commit.Hash.String()+".",
+		"Jane Smith", "jane@example.com", now.Add(-time.Hour), []string{"auth/session.go"})
+
+	// Newest first, like the git log
+	return []git.CommitInfo{reapplyLogin, revertLogin, revertSSO, login, sso}
+}
+
+func TestAnalyzeCommitsReverts(t *testing.T) {
+	now := time.Now()
+	commits := revertHistory(now)
+
+	auth := NewAnalyzer().AnalyzeCommits(commits)["Authentication"]
+	assert.Len(t, auth.Commits, 5)
+	assert.Empty(t, auth.Bugs, "reverting a fix is not a bug fix")
+	if assert.Len(t, auth.Reverts, 3) {
+		reverts := make(map[string]string)
+		for _, revert := range auth.Reverts {
+			assert.False(t, revert.Cancelled)
+			reverts[revert.CommitHash] = revert.RevertedHash
+		}
+		assert.Equal(t, commits[4].Commit.Hash.String(), reverts[commits[2].Commit.Hash.String()], "abbreviated hash")
+		assert.Equal(t, commits[3].Commit.Hash.String(), reverts[commits[1].Commit.Hash.String()], "subject")
+		assert.Equal(t, commits[1].Commit.Hash.String(), reverts[commits[0].Commit.Hash.String()], "revert of a revert")
+	}
+	assert.InDelta(t, 1.5, RevertRate(auth), 1e-9, "3 reverts of 2 changes")
+}
+
+func TestAnalyzeCommitsCancelReverts(t *testing.T) {
+	now := time.Now()
+	commits := revertHistory(now)
+
+	analyzer := NewAnalyzer()
+	analyzer.CancelReverts = true
+	auth := analyzer.AnalyzeCommits(commits)["Authentication"]
+
+	if assert.Len(t, auth.Commits, 1, "sso and its revert cancel out, and so do the login revert and its reapply") {
+		assert.Equal(t, commits[3].Commit.Hash.String(), auth.Commits[0].Commit.Hash.String())
+	}
+	assert.Equal(t, map[string]float64{"jane@example.com": 1}, auth.Owners)
+	assert.Len(t, auth.Reverts, 3)
+
+	cancelled := 0
+	for _, revert := range auth.Reverts {
+		if revert.Cancelled {
+			cancelled++
+		}
+	}
+	assert.Equal(t, 3, cancelled, "the login revert is undone by the reapply")
+	assert.InDelta(t, 1.5, RevertRate(auth), 1e-9, "the rate does not change when cancelling")
+}
@@ -0,0 +1,116 @@
+package features
+
+import (
+	"sort"
+	"strings"
+
+	"git-history-onboarding/internal/git"
+)
+
+// scopeAliasConfidence is higher than a regex scope match since the mapping is explicit
+const scopeAliasConfidence = 0.95
+
+// NormalizeScope folds case and separators so "Login_Svc" and "login-svc" are the same scope
+func NormalizeScope(scope string) string {
+	scope = strings.ToLower(strings.TrimSpace(scope))
+	return strings.NewReplacer("_", "-", " ", "-").Replace(scope)
+}
+
+// ScopeAliasDetector maps conventional commit scopes to canonical features, e.g.
+// "authn", "auth" and "login-svc" -> "Authentication"
+type ScopeAliasDetector struct {
+	aliases map[string]string // normalized scope -> feature
+}
+
+func NewScopeAliasDetector(aliases map[string]string) *ScopeAliasDetector {
+	normalized := make(map[string]string, len(aliases))
+	for scope, feature := range aliases {
+		normalized[NormalizeScope(scope)] = feature
+	}
+	return &ScopeAliasDetector{aliases: normalized}
+}
+
+func (d *ScopeAliasDetector) Name() string {
+	return "scopes"
+}
+
+func (d *ScopeAliasDetector) Features() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, feature := range d.aliases {
+		if !seen[feature] {
+			seen[feature] = true
+			names = append(names, feature)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (d *ScopeAliasDetector) Detect(commit git.CommitInfo) []FeatureMatch {
+	conventional := ParseConventionalCommit(commit.Commit.Message)
+	if conventional == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var matches []FeatureMatch
+	for _, scope := range conventional.Scopes {
+		alias := NormalizeScope(scope)
+		feature, ok := d.aliases[alias]
+		if !ok || seen[feature] {
+			continue
+		}
+		seen[feature] = true
+		matches = append(matches, FeatureMatch{
+			Feature:    feature,
+			Confidence: scopeAliasConfidence,
+			Detector:   d.Name(),
+			Rule:       RuleScope,
+			Pattern:    alias,
+			Text:       scope,
+		})
+	}
+	return matches
+}
+
+// ScopeCount is a conventional commit scope with the number of commits using it
+type ScopeCount struct {
+	Scope   string // normalized
+	Commits int
+}
+
+// UnmappedScopes counts the scopes missing from an alias table, most used first, so the
+// table can be kept current; with no aliases it lists every scope in use
+func UnmappedScopes(commits []git.CommitInfo, aliases map[string]string) []ScopeCount {
+	mapped := NewScopeAliasDetector(aliases).aliases
+
+	counts := make(map[string]int)
+	for _, commit := range commits {
+		conventional := ParseConventionalCommit(commit.Commit.Message)
+		if conventional == nil {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, scope := range conventional.Scopes {
+			scope = NormalizeScope(scope)
+			if _, ok := mapped[scope]; ok || seen[scope] {
+				continue
+			}
+			seen[scope] = true
+			counts[scope]++
+		}
+	}
+
+	unmapped := make([]ScopeCount, 0, len(counts))
+	for scope, count := range counts {
+		unmapped = append(unmapped, ScopeCount{Scope: scope, Commits: count})
+	}
+	sort.Slice(unmapped, func(i, j int) bool {
+		if unmapped[i].Commits != unmapped[j].Commits {
+			return unmapped[i].Commits > unmapped[j].Commits
+		}
+		return unmapped[i].Scope < unmapped[j].Scope
+	})
+	return unmapped
+}
@@ -0,0 +1,73 @@
+package features
+
+import (
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopeAliasDetector(t *testing.T) {
+	detector := NewScopeAliasDetector(map[string]string{
+		"authn":     "Authentication",
+		"auth":      "Authentication",
+		"Login_Svc": "Authentication",
+		"pay":       "Payment",
+	})
+	assert.Equal(t, []string{"Authentication", "Payment"}, detector.Features())
+
+	now := time.Now()
+	matches := detector.Detect(createTestCommit("abc123", "fix(login-svc, PAY, authn): expire sessions", "John Doe", "john@example.com", now, nil))
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, FeatureMatch{
+			Feature:    "Authentication",
+			Confidence: scopeAliasConfidence,
+			Detector:   "scopes",
+			Rule:       RuleScope,
+			Pattern:    "login-svc",
+			Text:       "login-svc",
+		}, matches[0])
+		assert.Equal(t, "Payment", matches[1].Feature)
+		assert.Equal(t, "PAY", matches[1].Text)
+	}
+
+	assert.Empty(t, detector.Detect(createTestCommit("def456", "fix(cart): totals", "John Doe", "john@example.com", now, nil)))
+	assert.Empty(t, detector.Detect(createTestCommit("789abc", "authn cleanup", "John Doe", "john@example.com", now, nil)))
+}
+
+func TestUnmappedScopes(t *testing.T) {
+	now := time.Now()
+	commits := []git.CommitInfo{
+		createTestCommit("aaa111", "feat(authn): sso", "John Doe", "john@example.com", now, nil),
+		createTestCommit("bbb222", "fix(cart, Checkout): totals", "John Doe", "john@example.com", now, nil),
+		createTestCommit("ccc333", "fix(cart): rounding", "John Doe", "john@example.com", now, nil),
+		createTestCommit("ddd444", "feat(search_ui): facets", "John Doe", "john@example.com", now, nil),
+		createTestCommit("eee555", "update readme", "John Doe", "john@example.com", now, nil),
+	}
+
+	unmapped := UnmappedScopes(commits, map[string]string{"authn": "Authentication", "search-ui": "Search"})
+	assert.Equal(t, []ScopeCount{{Scope: "cart", Commits: 2}, {Scope: "checkout", Commits: 1}}, unmapped)
+
+	assert.Len(t, UnmappedScopes(commits, nil), 4, "without aliases every scope is listed")
+}
+
+func TestAnalyzeCommitsScopeAliases(t *testing.T) {
+	config := models.FeatureDetectionConfig{
+		ScopeAliases: map[string]string{"authn": "Authentication", "login-svc": "Authentication"},
+		MergePolicy:  "first",
+	}
+	registry, err := NewRegistryFromConfig(config, fakeSource{})
+	assert.NoError(t, err)
+	assert.Equal(t, "scopes", registry.Detectors()[0].Name(), "aliases apply first unless placed explicitly")
+
+	now := time.Now()
+	commits := []git.CommitInfo{
+		createTestCommit("aaa111", "feat(authn): sso", "John Doe", "john@example.com", now, nil),
+		createTestCommit("bbb222", "fix(login-svc): token refresh", "Jane Smith", "jane@example.com", now, nil),
+	}
+	auth := NewAnalyzerWithRegistry(registry).AnalyzeCommits(commits)["Authentication"]
+	assert.Len(t, auth.Commits, 2)
+	assert.Equal(t, "scopes", auth.Assignments[commits[1].Commit.Hash.String()].Detector)
+}
@@ -0,0 +1,148 @@
+package szz
+
+import (
+	"sort"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/issues"
+	"git-history-onboarding/internal/models"
+)
+
+// BlameSource finds the commits that introduced the lines a commit changed
+type BlameSource interface {
+	ChangedLineOrigins(hash string, files []string) ([]git.LineOrigin, error)
+}
+
+// Analyzer identifies bug-introducing commits SZZ-style: the lines a fix deletes or
+// modifies are blamed on its parent, and the commits that last touched them are
+// taken as the ones that introduced the bug
+type Analyzer struct {
+	source BlameSource
+	cache  map[string][]models.BugOrigin
+}
+
+func NewAnalyzer(source BlameSource) *Analyzer {
+	return &Analyzer{
+		source: source,
+		cache:  make(map[string][]models.BugOrigin),
+	}
+}
+
+// AuthorFixCount is how many bugs were traced back to an author's changes
+type AuthorFixCount struct {
+	AuthorEmail string
+	Bugs        int
+}
+
+// FeatureStats summarizes the traced bugs of a feature
+type FeatureStats struct {
+	TracedBugs   int           // bugs with at least one introducing commit
+	MeanLifetime time.Duration // mean time from introduction to fix
+	Authors      []AuthorFixCount
+}
+
+// TraceFeatures fills IntroducedBy, IntroducedAt and ReportedAt on the bugs of every feature
+func (a *Analyzer) TraceFeatures(features map[string]*models.Feature) error {
+	for _, feature := range features {
+		for i := range feature.Bugs {
+			if err := a.TraceBug(&feature.Bugs[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TraceBug fills IntroducedBy and IntroducedAt on a single bug, and ReportedAt
+// from its linked issues when the tracker returned their creation times
+func (a *Analyzer) TraceBug(bug *models.Bug) error {
+	origins, err := a.origins(bug)
+	if err != nil {
+		return err
+	}
+
+	if bug.ReportedAt.IsZero() {
+		bug.ReportedAt = issues.EarliestCreated(bug.Issues)
+	}
+
+	bug.IntroducedBy = origins
+	bug.IntroducedAt = time.Time{}
+	if len(origins) > 0 {
+		bug.IntroducedAt = origins[0].Date
+	}
+	return nil
+}
+
+func (a *Analyzer) origins(bug *models.Bug) ([]models.BugOrigin, error) {
+	// The same fix is often shared by several features
+	if origins, ok := a.cache[bug.CommitHash]; ok {
+		return origins, nil
+	}
+
+	lines, err := a.source.ChangedLineOrigins(bug.CommitHash, bug.AffectedFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var origins []models.BugOrigin
+	for _, line := range lines {
+		if seen[line.CommitHash] || line.CommitHash == bug.CommitHash {
+			continue
+		}
+		seen[line.CommitHash] = true
+		origins = append(origins, models.BugOrigin{
+			CommitHash:  line.CommitHash,
+			AuthorEmail: line.AuthorEmail,
+			Date:        line.Date,
+		})
+	}
+
+	sort.SliceStable(origins, func(i, j int) bool {
+		return origins[i].Date.Before(origins[j].Date)
+	})
+
+	a.cache[bug.CommitHash] = origins
+	return origins, nil
+}
+
+// Stats reports how long the traced bugs of a feature lived and whose changes most
+// often needed fixing. Each author counts once per bug
+func Stats(feature *models.Feature) FeatureStats {
+	var stats FeatureStats
+	var total time.Duration
+	counts := make(map[string]int)
+
+	for _, bug := range feature.Bugs {
+		if len(bug.IntroducedBy) == 0 {
+			continue
+		}
+		stats.TracedBugs++
+		total += bug.FixedAt.Sub(bug.IntroducedAt)
+
+		authors := make(map[string]bool)
+		for _, origin := range bug.IntroducedBy {
+			if !authors[origin.AuthorEmail] {
+				authors[origin.AuthorEmail] = true
+				counts[origin.AuthorEmail]++
+			}
+		}
+	}
+
+	if stats.TracedBugs > 0 {
+		stats.MeanLifetime = total / time.Duration(stats.TracedBugs)
+	}
+
+	for email, count := range counts {
+		stats.Authors = append(stats.Authors, AuthorFixCount{AuthorEmail: email, Bugs: count})
+	}
+	sort.Slice(stats.Authors, func(i, j int) bool {
+		if stats.Authors[i].Bugs != stats.Authors[j].Bugs {
+			return stats.Authors[i].Bugs > stats.Authors[j].Bugs
+		}
+		return stats.Authors[i].AuthorEmail < stats.Authors[j].AuthorEmail
+	})
+
+	return stats
+}
@@ -0,0 +1,86 @@
+package szz
+
+import (
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBlame returns canned line origins per fix commit and counts lookups
+type fakeBlame struct {
+	origins map[string][]git.LineOrigin
+	calls   int
+}
+
+func (f *fakeBlame) ChangedLineOrigins(hash string, files []string) ([]git.LineOrigin, error) {
+	f.calls++
+	return f.origins[hash], nil
+}
+
+func TestTraceFeatures(t *testing.T) {
+	now := time.Now()
+	source := &fakeBlame{origins: map[string][]git.LineOrigin{
+		"fix1": {
+			{Path: "a.go", Line: 3, CommitHash: "intro2", AuthorEmail: "jane@example.com", Date: now.Add(-24 * time.Hour)},
+			{Path: "a.go", Line: 4, CommitHash: "intro1", AuthorEmail: "john@example.com", Date: now.Add(-72 * time.Hour)},
+			{Path: "a.go", Line: 5, CommitHash: "intro1", AuthorEmail: "john@example.com", Date: now.Add(-72 * time.Hour)},
+		},
+		"fix2": {
+			{Path: "b.go", Line: 1, CommitHash: "intro1", AuthorEmail: "john@example.com", Date: now.Add(-72 * time.Hour)},
+		},
+	}}
+
+	bug1 := models.Bug{CommitHash: "fix1", FixedAt: now}
+	bug2 := models.Bug{CommitHash: "fix2", FixedAt: now.Add(-48 * time.Hour)}
+	features := map[string]*models.Feature{
+		"API":  {Name: "API", Bugs: []models.Bug{bug1, bug2, {CommitHash: "fix3", FixedAt: now}}},
+		"Auth": {Name: "Auth", Bugs: []models.Bug{bug1}},
+	}
+
+	analyzer := NewAnalyzer(source)
+	assert.NoError(t, analyzer.TraceFeatures(features))
+	assert.Equal(t, 3, source.calls, "shared fixes are blamed once")
+
+	api := features["API"]
+	if assert.Len(t, api.Bugs[0].IntroducedBy, 2) {
+		assert.Equal(t, "intro1", api.Bugs[0].IntroducedBy[0].CommitHash)
+		assert.Equal(t, "intro2", api.Bugs[0].IntroducedBy[1].CommitHash)
+	}
+	assert.Equal(t, now.Add(-72*time.Hour), api.Bugs[0].IntroducedAt)
+	assert.Empty(t, api.Bugs[2].IntroducedBy)
+	assert.True(t, api.Bugs[2].IntroducedAt.IsZero())
+
+	stats := Stats(api)
+	assert.Equal(t, 2, stats.TracedBugs)
+	// (72h + 24h) / 2
+	assert.Equal(t, 48*time.Hour, stats.MeanLifetime)
+	assert.Equal(t, []AuthorFixCount{
+		{AuthorEmail: "john@example.com", Bugs: 2},
+		{AuthorEmail: "jane@example.com", Bugs: 1},
+	}, stats.Authors)
+}
+
+func TestStatsWithoutTracedBugs(t *testing.T) {
+	stats := Stats(&models.Feature{Bugs: []models.Bug{{CommitHash: "fix1"}}})
+	assert.Equal(t, 0, stats.TracedBugs)
+	assert.Equal(t, time.Duration(0), stats.MeanLifetime)
+	assert.Empty(t, stats.Authors)
+}
+
+func TestTraceBugReportedAt(t *testing.T) {
+	now := time.Now()
+	bug := models.Bug{CommitHash: "fix1", FixedAt: now, Issues: []models.IssueRef{
+		{Tracker: "github", ID: "12", CreatedAt: now.Add(-24 * time.Hour)},
+		{Tracker: "github", ID: "7", CreatedAt: now.Add(-48 * time.Hour)},
+		{Tracker: "jira", ID: "OPS-1"},
+	}}
+	assert.NoError(t, NewAnalyzer(&fakeBlame{}).TraceBug(&bug))
+	assert.Equal(t, now.Add(-48*time.Hour), bug.ReportedAt, "the earliest linked issue")
+
+	unlinked := models.Bug{CommitHash: "fix2", FixedAt: now, Issues: []models.IssueRef{{Tracker: "github", ID: "3"}}}
+	assert.NoError(t, NewAnalyzer(&fakeBlame{}).TraceBug(&unlinked))
+	assert.True(t, unlinked.ReportedAt.IsZero(), "issues without details leave it unset")
+}
@@ -0,0 +1,140 @@
+package timeline
+
+import (
+	"sort"
+	"time"
+
+	"git-history-onboarding/internal/analysis/features"
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+)
+
+// Unreleased names the commits after the newest tag
+const Unreleased = "Unreleased"
+
+// Source provides the tags and commit ranges of a repository
+type Source interface {
+	Tags() ([]git.Tag, error)
+	GetCommitRange(from, to string) ([]git.CommitInfo, error)
+}
+
+// Release summarizes the changes between a tag and the previous one
+type Release struct {
+	Tag             string // Unreleased for commits after the newest tag
+	Previous        string // previous tag, "" for the first release
+	CommitHash      string
+	Date            time.Time
+	Commits         []git.CommitInfo
+	Features        []FeatureActivity // most active first
+	BugFixes        []models.Bug
+	BreakingChanges []models.BreakingChange
+	Contributors    []Contributor // most commits first
+}
+
+// FeatureActivity counts a feature's commits and bug fixes in a release
+type FeatureActivity struct {
+	Feature  string
+	Commits  int
+	BugFixes int
+}
+
+// Contributor counts an author's commits in a release
+type Contributor struct {
+	AuthorEmail string
+	Commits     int
+}
+
+// Releases splits the history at every tag, oldest release first, and summarizes each
+// release with the analyzer. Commits after the newest tag form an Unreleased release
+func Releases(src Source, analyzer *features.Analyzer) ([]Release, error) {
+	tags, err := src.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []Release
+	previous := ""
+	for _, tag := range tags {
+		commits, err := src.GetCommitRange(previous, tag.Name)
+		if err != nil {
+			return nil, err
+		}
+		release := Summarize(commits, analyzer)
+		release.Tag = tag.Name
+		release.Previous = previous
+		release.CommitHash = tag.CommitHash
+		release.Date = tag.Date
+		releases = append(releases, release)
+		previous = tag.Name
+	}
+
+	commits, err := src.GetCommitRange(previous, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) > 0 {
+		release := Summarize(commits, analyzer)
+		release.Tag = Unreleased
+		release.Previous = previous
+		release.CommitHash = commits[0].Commit.Hash.String()
+		release.Date = commits[0].Commit.Author.When
+		releases = append(releases, release)
+	}
+
+	return releases, nil
+}
+
+// Summarize computes the features touched, bugs fixed, breaking changes and
+// contributors of a set of commits
+func Summarize(commits []git.CommitInfo, analyzer *features.Analyzer) Release {
+	release := Release{Commits: commits}
+
+	bugs := make(map[string]bool)
+	for name, feature := range analyzer.AnalyzeCommits(commits) {
+		if len(feature.Commits) == 0 {
+			continue
+		}
+		release.Features = append(release.Features, FeatureActivity{
+			Feature:  name,
+			Commits:  len(feature.Commits),
+			BugFixes: len(feature.Bugs),
+		})
+		for _, bug := range feature.Bugs {
+			if !bugs[bug.CommitHash] {
+				bugs[bug.CommitHash] = true
+				release.BugFixes = append(release.BugFixes, bug)
+			}
+		}
+	}
+	sort.Slice(release.Features, func(i, j int) bool {
+		if release.Features[i].Commits != release.Features[j].Commits {
+			return release.Features[i].Commits > release.Features[j].Commits
+		}
+		return release.Features[i].Feature < release.Features[j].Feature
+	})
+	sort.SliceStable(release.BugFixes, func(i, j int) bool {
+		return release.BugFixes[i].FixedAt.Before(release.BugFixes[j].FixedAt)
+	})
+
+	counts := make(map[string]int)
+	for _, commit := range commits {
+		counts[commit.Commit.Author.Email]++
+		if change, ok := features.ParseBreakingChange(commit); ok {
+			release.BreakingChanges = append(release.BreakingChanges, change)
+		}
+	}
+	for email, count := range counts {
+		release.Contributors = append(release.Contributors, Contributor{AuthorEmail: email, Commits: count})
+	}
+	sort.Slice(release.Contributors, func(i, j int) bool {
+		if release.Contributors[i].Commits != release.Contributors[j].Commits {
+			return release.Contributors[i].Commits > release.Contributors[j].Commits
+		}
+		return release.Contributors[i].AuthorEmail < release.Contributors[j].AuthorEmail
+	})
+	sort.SliceStable(release.BreakingChanges, func(i, j int) bool {
+		return release.BreakingChanges[i].Date.Before(release.BreakingChanges[j].Date)
+	})
+
+	return release
+}
@@ -0,0 +1,109 @@
+package timeline
+
+import (
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/analysis/features"
+	"git-history-onboarding/internal/git"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestCommit(hash string, message string, email string, when time.Time, files []string) git.CommitInfo {
+	return git.CommitInfo{
+		Commit: &object.Commit{
+			Hash: plumbing.NewHash(hash),
+			Author: object.Signature{
+				Name:  email,
+				Email: email,
+				When:  when,
+			},
+			Message: message,
+		},
+		Files: files,
+	}
+}
+
+// fakeSource serves a linear history, newest first, with tags by commit index
+type fakeSource struct {
+	commits []git.CommitInfo
+	tags    []git.Tag
+}
+
+func (f fakeSource) Tags() ([]git.Tag, error) {
+	return f.tags, nil
+}
+
+func (f fakeSource) GetCommitRange(from, to string) ([]git.CommitInfo, error) {
+	index := func(name string, fallback int) int {
+		for _, tag := range f.tags {
+			if tag.Name == name {
+				for i, commit := range f.commits {
+					if commit.Commit.Hash.String() == tag.CommitHash {
+						return i
+					}
+				}
+			}
+		}
+		return fallback
+	}
+	return f.commits[index(to, 0):index(from, len(f.commits))], nil
+}
+
+func TestReleases(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	commits := []git.CommitInfo{
+		createTestCommit("ffff06", "feat(search): facets", "jane@example.com", start.Add(6*day), []string{"search/facets.go"}),
+		createTestCommit("eeee05", "feat(api)!: drop v1 endpoints", "john@example.com", start.Add(5*day), []string{"api/v1.go"}),
+		createTestCommit("dddd04", "fix(auth): refresh tokens", "jane@example.com", start.Add(4*day), []string{"auth/token.go"}),
+		createTestCommit("cccc03", "fix(auth): session expiry", "john@example.com", start.Add(3*day), []string{"auth/session.go"}),
+		createTestCommit("bbbb02", "feat(api): users endpoint", "john@example.com", start.Add(2*day), []string{"api/users.go"}),
+		createTestCommit("aaaa01", "feat(auth): login", "john@example.com", start.Add(day), []string{"auth/login.go"}),
+	}
+	src := fakeSource{
+		commits: commits,
+		tags: []git.Tag{
+			{Name: "v1.0.0", CommitHash: commits[4].Commit.Hash.String(), Date: start.Add(2 * day)},
+			{Name: "v2.0.0", CommitHash: commits[1].Commit.Hash.String(), Date: start.Add(5 * day)},
+		},
+	}
+
+	releases, err := Releases(src, features.NewAnalyzer())
+	assert.NoError(t, err)
+	if !assert.Len(t, releases, 3) {
+		return
+	}
+
+	first := releases[0]
+	assert.Equal(t, "v1.0.0", first.Tag)
+	assert.Empty(t, first.Previous)
+	assert.Len(t, first.Commits, 2)
+	assert.Empty(t, first.BugFixes)
+	assert.Equal(t, []Contributor{{AuthorEmail: "john@example.com", Commits: 2}}, first.Contributors)
+
+	second := releases[1]
+	assert.Equal(t, "v2.0.0", second.Tag)
+	assert.Equal(t, "v1.0.0", second.Previous)
+	assert.Equal(t, start.Add(5*day), second.Date)
+	assert.Len(t, second.Commits, 3)
+	if assert.Len(t, second.BugFixes, 2) {
+		assert.Equal(t, commits[3].Commit.Hash.String(), second.BugFixes[0].CommitHash, "oldest fix first")
+	}
+	if assert.Len(t, second.BreakingChanges, 1) {
+		assert.Equal(t, "drop v1 endpoints", second.BreakingChanges[0].Description)
+	}
+	assert.Equal(t, FeatureActivity{Feature: "Authentication", Commits: 2, BugFixes: 2}, second.Features[0])
+	assert.Equal(t, []Contributor{
+		{AuthorEmail: "john@example.com", Commits: 2},
+		{AuthorEmail: "jane@example.com", Commits: 1},
+	}, second.Contributors)
+
+	unreleased := releases[2]
+	assert.Equal(t, Unreleased, unreleased.Tag)
+	assert.Equal(t, "v2.0.0", unreleased.Previous)
+	assert.Equal(t, commits[0].Commit.Hash.String(), unreleased.CommitHash)
+	assert.Len(t, unreleased.Commits, 1)
+}
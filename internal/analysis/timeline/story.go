@@ -0,0 +1,312 @@
+package timeline
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"git-history-onboarding/internal/analysis/features"
+	"git-history-onboarding/internal/display"
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+)
+
+// PhaseKind describes what happened during a phase of a feature's history
+type PhaseKind string
+
+const (
+	PhaseCreation    PhaseKind = "creation"
+	PhaseDevelopment PhaseKind = "development"
+	PhaseBugCluster  PhaseKind = "bug cluster"
+	PhaseRefactoring PhaseKind = "refactoring"
+)
+
+// Defaults for StoryOptions
+const (
+	DefaultGap             = 30 * 24 * time.Hour
+	DefaultMinPhaseCommits = 3
+)
+
+// Share of a phase's commits from which it counts as a bug cluster or a refactoring
+const dominantShare = 0.5
+
+var refactorPattern = regexp.MustCompile(`(?i)\b(refactor\w*|rewr[io]te|rework\w*|restructur\w*|clean ?up|migrat\w*)\b`)
+
+// StoryOptions tune how a feature's history is split into phases
+type StoryOptions struct {
+	Gap             time.Duration // quiet period that ends an activity burst; DefaultGap when zero
+	MinPhaseCommits int           // consecutive commits by a new author that hand a phase over; DefaultMinPhaseCommits when zero
+}
+
+// Phase is a stretch of a feature's history with one lead author
+type Phase struct {
+	Kind       PhaseKind
+	Start      time.Time
+	End        time.Time
+	Commits    []git.CommitInfo // oldest first
+	BugFixes   int
+	Refactors  int
+	Lead       string // author with the most commits in the phase
+	LeadShare  float64
+	KeyCommits []git.CommitInfo // breaking changes and the largest commits, oldest first
+	QuietDays  int              // days without commits before the phase
+}
+
+// Story is the chronological narrative of a feature
+type Story struct {
+	Feature      string
+	CreatedAt    time.Time
+	Creator      string
+	Commits      int
+	Contributors int
+	BugFixes     int
+	Phases       []Phase
+}
+
+// Tell segments a feature's history into phases at activity gaps and ownership
+// handoffs, and classifies each phase by its commits
+func Tell(feature *models.Feature, opts StoryOptions) Story {
+	if opts.Gap <= 0 {
+		opts.Gap = DefaultGap
+	}
+	if opts.MinPhaseCommits <= 0 {
+		opts.MinPhaseCommits = DefaultMinPhaseCommits
+	}
+
+	commits := make([]git.CommitInfo, len(feature.Commits))
+	copy(commits, feature.Commits)
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Commit.Author.When.Before(commits[j].Commit.Author.When)
+	})
+
+	bugs := make(map[string]bool, len(feature.Bugs))
+	for _, bug := range feature.Bugs {
+		bugs[bug.CommitHash] = true
+	}
+
+	story := Story{Feature: feature.Name, Commits: len(commits), BugFixes: len(bugs)}
+	if len(commits) == 0 {
+		return story
+	}
+	story.CreatedAt = commits[0].Commit.Author.When
+	story.Creator = commits[0].Commit.Author.Email
+
+	authors := make(map[string]bool)
+	for _, commit := range commits {
+		authors[commit.Commit.Author.Email] = true
+	}
+	story.Contributors = len(authors)
+
+	var previousEnd time.Time
+	for _, burst := range splitBursts(commits, opts.Gap) {
+		for i, segment := range splitHandoffs(burst, opts.MinPhaseCommits) {
+			phase := newPhase(segment, bugs)
+			if i == 0 && !previousEnd.IsZero() {
+				phase.QuietDays = int(phase.Start.Sub(previousEnd).Hours() / 24)
+			}
+			if len(story.Phases) == 0 {
+				phase.Kind = PhaseCreation
+			}
+			story.Phases = append(story.Phases, phase)
+			previousEnd = phase.End
+		}
+	}
+	return story
+}
+
+// splitBursts starts a new segment after every gap longer than gap
+func splitBursts(commits []git.CommitInfo, gap time.Duration) [][]git.CommitInfo {
+	var bursts [][]git.CommitInfo
+	start := 0
+	for i := 1; i < len(commits); i++ {
+		if commits[i].Commit.Author.When.Sub(commits[i-1].Commit.Author.When) > gap {
+			bursts = append(bursts, commits[start:i])
+			start = i
+		}
+	}
+	return append(bursts, commits[start:])
+}
+
+// splitHandoffs starts a new segment where at least minRun consecutive commits come
+// from someone other than the lead so far, once the current segment has minRun commits
+func splitHandoffs(commits []git.CommitInfo, minRun int) [][]git.CommitInfo {
+	var segments [][]git.CommitInfo
+	start := 0
+	for i := 0; i < len(commits); {
+		author := commits[i].Commit.Author.Email
+		end := i
+		for end < len(commits) && commits[end].Commit.Author.Email == author {
+			end++
+		}
+		if end-i >= minRun && i-start >= minRun {
+			if lead, _ := leadAuthor(commits[start:i]); lead != author {
+				segments = append(segments, commits[start:i])
+				start = i
+			}
+		}
+		i = end
+	}
+	return append(segments, commits[start:])
+}
+
+func newPhase(commits []git.CommitInfo, bugs map[string]bool) Phase {
+	phase := Phase{
+		Kind:    PhaseDevelopment,
+		Start:   commits[0].Commit.Author.When,
+		End:     commits[len(commits)-1].Commit.Author.When,
+		Commits: commits,
+	}
+	phase.Lead, phase.LeadShare = leadAuthor(commits)
+
+	for _, commit := range commits {
+		if bugs[commit.Commit.Hash.String()] {
+			phase.BugFixes++
+		}
+		if isRefactor(commit) {
+			phase.Refactors++
+		}
+	}
+	switch total := float64(len(commits)); {
+	case float64(phase.BugFixes)/total >= dominantShare:
+		phase.Kind = PhaseBugCluster
+	case float64(phase.Refactors)/total >= dominantShare:
+		phase.Kind = PhaseRefactoring
+	}
+
+	phase.KeyCommits = keyCommits(commits, 3)
+	return phase
+}
+
+// leadAuthor returns the author with the most commits and their share
+func leadAuthor(commits []git.CommitInfo) (string, float64) {
+	counts := make(map[string]int)
+	for _, commit := range commits {
+		counts[commit.Commit.Author.Email]++
+	}
+	lead := ""
+	for email, count := range counts {
+		if lead == "" || count > counts[lead] || count == counts[lead] && email < lead {
+			lead = email
+		}
+	}
+	if len(commits) == 0 {
+		return lead, 0
+	}
+	return lead, float64(counts[lead]) / float64(len(commits))
+}
+
+func isRefactor(commit git.CommitInfo) bool {
+	if conventional := features.ParseConventionalCommit(commit.Commit.Message); conventional != nil {
+		if strings.EqualFold(conventional.Type, "refactor") {
+			return true
+		}
+		return refactorPattern.MatchString(conventional.Description)
+	}
+	return refactorPattern.MatchString(display.FirstLine(commit.Commit.Message))
+}
+
+// keyCommits picks breaking changes first, then the commits touching the most files
+func keyCommits(commits []git.CommitInfo, n int) []git.CommitInfo {
+	ranked := make([]git.CommitInfo, len(commits))
+	copy(ranked, commits)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		_, breakingI := features.ParseBreakingChange(ranked[i])
+		_, breakingJ := features.ParseBreakingChange(ranked[j])
+		if breakingI != breakingJ {
+			return breakingI
+		}
+		return len(ranked[i].Files) > len(ranked[j].Files)
+	})
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Commit.Author.When.Before(ranked[j].Commit.Author.When)
+	})
+	return ranked
+}
+
+// Markdown renders the story as prose; commitURL formats links to commits, e.g.
+// "https://github.com/acme/shop/commit/%s", and may be empty
+func (s Story) Markdown(commitURL string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# The Story of %s\n\n", s.Feature)
+	if len(s.Phases) == 0 {
+		b.WriteString("This feature has no commits yet.\n")
+		return b.String()
+	}
+
+	first := s.Phases[0].Commits[0]
+	fmt.Fprintf(&b, "%s started on %s, when %s committed %s. ",
+		s.Feature, display.Date(s.CreatedAt), s.Creator, commitLink(first, commitURL))
+	fmt.Fprintf(&b, "Since then it has seen %s from %s, including %s.\n",
+		display.Plural(s.Commits, "commit"), display.Plural(s.Contributors, "contributor"), display.Plural(s.BugFixes, "bug fix"))
+
+	for i, phase := range s.Phases {
+		fmt.Fprintf(&b, "\n## Phase %d: %s (%s to %s)\n\n", i+1, phaseTitle(phase.Kind), display.Date(phase.Start), display.Date(phase.End))
+
+		if phase.QuietDays > 0 {
+			fmt.Fprintf(&b, "After %d quiet days, work resumed. ", phase.QuietDays)
+		}
+		if i > 0 && s.Phases[i-1].Lead != phase.Lead {
+			fmt.Fprintf(&b, "Ownership passed from %s to %s. ", s.Phases[i-1].Lead, phase.Lead)
+		}
+
+		switch phase.Kind {
+		case PhaseCreation:
+			fmt.Fprintf(&b, "%s laid the groundwork with %d of the phase's %s.",
+				phase.Lead, leadCommits(phase), display.Plural(len(phase.Commits), "commit"))
+		case PhaseBugCluster:
+			fmt.Fprintf(&b, "A cluster of %s in %s kept the team busy, with %s fixing the most.",
+				display.Plural(phase.BugFixes, "bug fix"), display.Plural(len(phase.Commits), "commit"), phase.Lead)
+		case PhaseRefactoring:
+			fmt.Fprintf(&b, "%s led a round of refactoring: %d of %s restructured the code.",
+				phase.Lead, phase.Refactors, display.Plural(len(phase.Commits), "commit"))
+		default:
+			fmt.Fprintf(&b, "%s drove development with %d of %s.",
+				phase.Lead, leadCommits(phase), display.Plural(len(phase.Commits), "commit"))
+		}
+		if phase.Kind != PhaseBugCluster && phase.BugFixes > 0 {
+			fmt.Fprintf(&b, " The phase included %s.", display.Plural(phase.BugFixes, "bug fix"))
+		}
+		b.WriteString("\n")
+
+		if len(phase.KeyCommits) > 0 {
+			b.WriteString("\nKey commits:\n\n")
+			for _, commit := range phase.KeyCommits {
+				fmt.Fprintf(&b, "- %s by %s on %s (%s)\n",
+					commitLink(commit, commitURL), commit.Commit.Author.Email,
+					display.Date(commit.Commit.Author.When), display.Plural(len(commit.Files), "file"))
+			}
+		}
+	}
+	return b.String()
+}
+
+func phaseTitle(kind PhaseKind) string {
+	switch kind {
+	case PhaseCreation:
+		return "Creation"
+	case PhaseBugCluster:
+		return "Bug Fixing"
+	case PhaseRefactoring:
+		return "Refactoring"
+	default:
+		return "Development"
+	}
+}
+
+func leadCommits(phase Phase) int {
+	return int(phase.LeadShare*float64(len(phase.Commits)) + 0.5)
+}
+
+func commitLink(commit git.CommitInfo, commitURL string) string {
+	hash := commit.Commit.Hash.String()
+	subject := fmt.Sprintf("%q", display.FirstLine(commit.Commit.Message))
+	if commitURL == "" {
+		return fmt.Sprintf("%s (%s)", subject, display.ShortHash(hash))
+	}
+	return fmt.Sprintf("%s ([%s](%s))", subject, display.ShortHash(hash), fmt.Sprintf(commitURL, hash))
+}
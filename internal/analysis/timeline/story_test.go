@@ -0,0 +1,106 @@
+package timeline
+
+import (
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTell(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	commits := []git.CommitInfo{
+		// Creation: john builds the feature
+		createTestCommit("aaaa01", "feat(auth): login", "john@example.com", start, []string{"auth/login.go", "auth/user.go"}),
+		createTestCommit("aaaa02", "feat(auth): logout", "john@example.com", start.Add(day), []string{"auth/logout.go"}),
+		createTestCommit("aaaa03", "feat(auth): sessions", "john@example.com", start.Add(2*day), []string{"auth/session.go"}),
+		// Two months later, jane fixes a cluster of bugs
+		createTestCommit("bbbb01", "fix(auth): session expiry", "jane@example.com", start.Add(62*day), []string{"auth/session.go"}),
+		createTestCommit("bbbb02", "fix(auth): logout redirect", "jane@example.com", start.Add(63*day), []string{"auth/logout.go"}),
+		createTestCommit("bbbb03", "fix(auth): login throttling", "jane@example.com", start.Add(64*day), []string{"auth/login.go"}),
+		// Without a pause, bob takes over and restructures the code
+		createTestCommit("cccc01", "refactor(auth): split session store", "bob@example.com", start.Add(70*day), []string{"auth/session.go", "auth/store.go", "auth/memory.go"}),
+		createTestCommit("cccc02", "refactor(auth): extract token helpers", "bob@example.com", start.Add(71*day), []string{"auth/token.go"}),
+		createTestCommit("cccc03", "feat(auth)!: require MFA", "bob@example.com", start.Add(72*day), []string{"auth/mfa.go"}),
+	}
+	feature := &models.Feature{
+		Name:    "Authentication",
+		Commits: commits,
+		Bugs: []models.Bug{
+			{CommitHash: commits[3].Commit.Hash.String()},
+			{CommitHash: commits[4].Commit.Hash.String()},
+			{CommitHash: commits[5].Commit.Hash.String()},
+		},
+	}
+
+	story := Tell(feature, StoryOptions{})
+	assert.Equal(t, start, story.CreatedAt)
+	assert.Equal(t, "john@example.com", story.Creator)
+	assert.Equal(t, 9, story.Commits)
+	assert.Equal(t, 3, story.Contributors)
+	assert.Equal(t, 3, story.BugFixes)
+	if !assert.Len(t, story.Phases, 3) {
+		return
+	}
+
+	creation := story.Phases[0]
+	assert.Equal(t, PhaseCreation, creation.Kind)
+	assert.Equal(t, "john@example.com", creation.Lead)
+	assert.Len(t, creation.Commits, 3)
+	assert.Zero(t, creation.QuietDays)
+
+	bugs := story.Phases[1]
+	assert.Equal(t, PhaseBugCluster, bugs.Kind)
+	assert.Equal(t, "jane@example.com", bugs.Lead)
+	assert.Equal(t, 3, bugs.BugFixes)
+	assert.Equal(t, 60, bugs.QuietDays)
+
+	refactoring := story.Phases[2]
+	assert.Equal(t, PhaseRefactoring, refactoring.Kind, "split at the handoff to bob, not at a gap")
+	assert.Equal(t, "bob@example.com", refactoring.Lead)
+	assert.Equal(t, 2, refactoring.Refactors)
+	assert.Zero(t, refactoring.QuietDays)
+	if assert.Len(t, refactoring.KeyCommits, 3) {
+		assert.Equal(t, commits[6].Commit.Hash, refactoring.KeyCommits[0].Commit.Hash, "key commits are in history order")
+	}
+
+	markdown := story.Markdown("https://github.com/acme/shop/commit/%s")
+	assert.Contains(t, markdown, "# The Story of Authentication")
+	assert.Contains(t, markdown, "Authentication started on 2024-01-01, when john@example.com committed \"feat(auth): login\" ([aaaa010](https://github.com/acme/shop/commit/aaaa01")
+	assert.Contains(t, markdown, "9 commits from 3 contributors, including 3 bug fixes")
+	assert.Contains(t, markdown, "## Phase 2: Bug Fixing (2024-03-03 to 2024-03-05)")
+	assert.Contains(t, markdown, "After 60 quiet days, work resumed. Ownership passed from john@example.com to jane@example.com.")
+	assert.Contains(t, markdown, "bob@example.com led a round of refactoring: 2 of 3 commits restructured the code.")
+	assert.Contains(t, markdown, "\"feat(auth)!: require MFA\" ([cccc030]")
+}
+
+func TestTellHandoffNeedsSustainedRun(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	hour := time.Hour
+	commits := []git.CommitInfo{
+		createTestCommit("aaaa01", "feat: a", "john@example.com", start, nil),
+		createTestCommit("aaaa02", "feat: b", "john@example.com", start.Add(hour), nil),
+		createTestCommit("aaaa03", "feat: c", "john@example.com", start.Add(2*hour), nil),
+		createTestCommit("aaaa04", "feat: d", "jane@example.com", start.Add(3*hour), nil),
+		createTestCommit("aaaa05", "feat: e", "jane@example.com", start.Add(4*hour), nil),
+		createTestCommit("aaaa06", "feat: f", "john@example.com", start.Add(5*hour), nil),
+	}
+
+	story := Tell(&models.Feature{Name: "Search", Commits: commits}, StoryOptions{})
+	assert.Len(t, story.Phases, 1, "two commits by jane are not a handoff")
+
+	story = Tell(&models.Feature{Name: "Search", Commits: commits}, StoryOptions{MinPhaseCommits: 2})
+	if assert.Len(t, story.Phases, 2, "john's last commit alone does not take the feature back") {
+		assert.Equal(t, "jane@example.com", story.Phases[1].Lead)
+		assert.Equal(t, PhaseDevelopment, story.Phases[1].Kind)
+	}
+}
+
+func TestTellEmptyFeature(t *testing.T) {
+	story := Tell(&models.Feature{Name: "Search"}, StoryOptions{})
+	assert.Empty(t, story.Phases)
+	assert.Contains(t, story.Markdown(""), "no commits yet")
+}
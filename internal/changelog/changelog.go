@@ -0,0 +1,212 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"git-history-onboarding/internal/analysis/features"
+	"git-history-onboarding/internal/display"
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/issues"
+	"git-history-onboarding/internal/models"
+)
+
+// Keep a Changelog section titles, in the order they are rendered
+const (
+	SectionAdded      = "Added"
+	SectionChanged    = "Changed"
+	SectionDeprecated = "Deprecated"
+	SectionRemoved    = "Removed"
+	SectionFixed      = "Fixed"
+	SectionSecurity   = "Security"
+)
+
+var sectionOrder = []string{SectionAdded, SectionChanged, SectionDeprecated, SectionRemoved, SectionFixed, SectionSecurity}
+
+// typeSections maps conventional commit types to sections; other types are left out
+var typeSections = map[string]string{
+	"feat":      SectionAdded,
+	"perf":      SectionChanged,
+	"refactor":  SectionChanged,
+	"deprecate": SectionDeprecated,
+	"revert":    SectionRemoved,
+	"remove":    SectionRemoved,
+	"fix":       SectionFixed,
+	"security":  SectionSecurity,
+}
+
+// Unreleased is the version of changes not yet tagged
+const Unreleased = "Unreleased"
+
+// Entry is one changelog line
+type Entry struct {
+	Hash        string            `json:"hash"`
+	Type        string            `json:"type"`
+	Scope       string            `json:"scope,omitempty"`
+	Feature     string            `json:"feature,omitempty"`
+	Description string            `json:"description"`
+	Breaking    bool              `json:"breaking,omitempty"`
+	Issues      []models.IssueRef `json:"issues,omitempty"`
+}
+
+// Section groups entries under a Keep a Changelog heading, e.g. "Added"
+type Section struct {
+	Title   string  `json:"title"`
+	Entries []Entry `json:"entries"`
+}
+
+// Release is the changelog of one version
+type Release struct {
+	Version  string    `json:"version"`
+	Date     string    `json:"date,omitempty"` // YYYY-MM-DD, empty when unreleased
+	Sections []Section `json:"sections"`
+}
+
+// Links turns commit hashes and issue references into URLs, e.g.
+// "https://github.com/acme/shop/commit/%s"; empty formats leave them unlinked
+type Links struct {
+	Commit string
+	Issues map[string]string // tracker -> URL format taking the issue ID
+}
+
+// Build groups conventional commits by section and, within a section, by feature.
+// featureOf names the feature of each commit hash; commits that are not conventional
+// or whose type has no section are left out
+func Build(version string, date time.Time, commits []git.CommitInfo, featureOf map[string]string, extractor *issues.Extractor) Release {
+	bySection := make(map[string][]Entry)
+	for _, commit := range commits {
+		conventional := features.ParseConventionalCommit(commit.Commit.Message)
+		if conventional == nil {
+			continue
+		}
+		typ := strings.ToLower(conventional.Type)
+		section, ok := typeSections[typ]
+		if !ok && conventional.Breaking {
+			section, ok = SectionChanged, true
+		}
+		if !ok {
+			continue
+		}
+
+		hash := commit.Commit.Hash.String()
+		entry := Entry{
+			Hash:        hash,
+			Type:        typ,
+			Scope:       conventional.Scope,
+			Feature:     featureOf[hash],
+			Description: conventional.Description,
+			Breaking:    conventional.Breaking,
+		}
+		if extractor != nil {
+			entry.Issues = extractor.Extract(commit.Commit.Message)
+		}
+		bySection[section] = append(bySection[section], entry)
+	}
+
+	release := Release{Version: version}
+	if !date.IsZero() {
+		release.Date = date.Format(display.DateLayout)
+	}
+	for _, title := range sectionOrder {
+		entries := bySection[title]
+		if len(entries) == 0 {
+			continue
+		}
+		// Breaking changes first, then by feature, keeping history order within a feature
+		sort.SliceStable(entries, func(i, j int) bool {
+			if entries[i].Breaking != entries[j].Breaking {
+				return entries[i].Breaking
+			}
+			return entries[i].Feature < entries[j].Feature
+		})
+		release.Sections = append(release.Sections, Section{Title: title, Entries: entries})
+	}
+	return release
+}
+
+// Empty reports whether the release has no entries
+func (r Release) Empty() bool {
+	return len(r.Sections) == 0
+}
+
+// Without returns the release minus the entries whose commits are in skip
+func (r Release) Without(skip func(hash string) bool) Release {
+	filtered := Release{Version: r.Version, Date: r.Date}
+	for _, section := range r.Sections {
+		var entries []Entry
+		for _, entry := range section.Entries {
+			if !skip(entry.Hash) {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) > 0 {
+			filtered.Sections = append(filtered.Sections, Section{Title: section.Title, Entries: entries})
+		}
+	}
+	return filtered
+}
+
+// Markdown renders the release as a Keep a Changelog version block
+func (r Release) Markdown(links Links) string {
+	var b strings.Builder
+	if r.Date == "" {
+		fmt.Fprintf(&b, "## [%s]\n", r.Version)
+	} else {
+		fmt.Fprintf(&b, "## [%s] - %s\n", r.Version, r.Date)
+	}
+
+	for _, section := range r.Sections {
+		fmt.Fprintf(&b, "\n### %s\n\n", section.Title)
+		b.WriteString(section.markdown(links))
+	}
+	return b.String()
+}
+
+// markdown renders the entries of a section as list items
+func (s Section) markdown(links Links) string {
+	var b strings.Builder
+	for _, entry := range s.Entries {
+		b.WriteString("- ")
+		if entry.Breaking {
+			b.WriteString("**BREAKING** ")
+		}
+		if entry.Feature != "" {
+			fmt.Fprintf(&b, "**%s**: ", entry.Feature)
+		}
+		b.WriteString(entry.Description)
+		fmt.Fprintf(&b, " (%s)", links.commit(entry.Hash))
+		for _, ref := range entry.Issues {
+			fmt.Fprintf(&b, " %s", links.issue(ref))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// JSON renders the release as indented JSON
+func (r Release) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+func (l Links) commit(hash string) string {
+	short := display.ShortHash(hash)
+	if l.Commit == "" {
+		return short
+	}
+	return fmt.Sprintf("[%s](%s)", short, fmt.Sprintf(l.Commit, hash))
+}
+
+func (l Links) issue(ref models.IssueRef) string {
+	label := ref.ID
+	if ref.Tracker == issues.TrackerGitHub || ref.Tracker == issues.TrackerGitLab {
+		label = "#" + ref.ID
+	}
+	format, ok := l.Issues[ref.Tracker]
+	if !ok || format == "" {
+		return label
+	}
+	return fmt.Sprintf("[%s](%s)", label, fmt.Sprintf(format, ref.ID))
+}
@@ -0,0 +1,193 @@
+package changelog
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/issues"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestCommit(hash string, message string) git.CommitInfo {
+	return git.CommitInfo{
+		Commit: &object.Commit{
+			Hash:    plumbing.NewHash(hash),
+			Author:  object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+			Message: message,
+		},
+	}
+}
+
+func testCommits() []git.CommitInfo {
+	return []git.CommitInfo{
+		createTestCommit("aaa111", "feat(auth): add sso (#12)"),
+		createTestCommit("bbb222", "fix(cart): round totals\n\nFixes PAY-7"),
+		createTestCommit("ccc333", "docs: update readme"),
+		createTestCommit("ddd444", "feat(api)!: drop v1 endpoints"),
+		createTestCommit("eee555", "Merge pull request #13"),
+		createTestCommit("fff666", "feat(cart): coupons"),
+	}
+}
+
+func testFeatures(commits []git.CommitInfo) map[string]string {
+	return map[string]string{
+		commits[0].Commit.Hash.String(): "Authentication",
+		commits[1].Commit.Hash.String(): "Payment",
+		commits[3].Commit.Hash.String(): "API",
+		commits[5].Commit.Hash.String(): "Payment",
+	}
+}
+
+var testLinks = Links{
+	Commit: "https://github.com/acme/shop/commit/%s",
+	Issues: map[string]string{issues.TrackerGitHub: "https://github.com/acme/shop/issues/%s"},
+}
+
+func TestBuild(t *testing.T) {
+	commits := testCommits()
+	release := Build("v2.0.0", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), commits, testFeatures(commits), issues.DefaultExtractor())
+
+	if assert.Len(t, release.Sections, 2) {
+		added := release.Sections[0]
+		assert.Equal(t, SectionAdded, added.Title)
+		var descriptions []string
+		for _, entry := range added.Entries {
+			descriptions = append(descriptions, entry.Description)
+		}
+		assert.Equal(t, []string{"drop v1 endpoints", "add sso (#12)", "coupons"}, descriptions,
+			"breaking changes first, then grouped by feature")
+		assert.True(t, added.Entries[0].Breaking)
+
+		fixed := release.Sections[1]
+		assert.Equal(t, SectionFixed, fixed.Title)
+		assert.Equal(t, "cart", fixed.Entries[0].Scope)
+		assert.Equal(t, "PAY-7", fixed.Entries[0].Issues[0].ID)
+	}
+
+	data, err := release.JSON()
+	assert.NoError(t, err)
+	var decoded Release
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, release, decoded)
+}
+
+func TestMarkdown(t *testing.T) {
+	commits := testCommits()
+	release := Build("v2.0.0", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), commits[:2], testFeatures(commits), issues.DefaultExtractor())
+
+	expected := `## [v2.0.0] - 2024-05-01
+
+### Added
+
+- **Authentication**: add sso (#12) ([aaa1110](https://github.com/acme/shop/commit/aaa1110000000000000000000000000000000000)) [#12](https://github.com/acme/shop/issues/12)
+
+### Fixed
+
+- **Payment**: round totals ([bbb2220](https://github.com/acme/shop/commit/bbb2220000000000000000000000000000000000)) PAY-7
+`
+	assert.Equal(t, expected, release.Markdown(testLinks))
+
+	unreleased := Build(Unreleased, time.Time{}, commits[1:2], nil, nil)
+	assert.Equal(t, "## [Unreleased]\n\n### Fixed\n\n- round totals (bbb2220)\n", unreleased.Markdown(Links{}))
+}
+
+func TestPrepend(t *testing.T) {
+	commits := testCommits()
+	featureOf := testFeatures(commits)
+	first := Build("v1.0.0", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), commits[:2], featureOf, nil)
+
+	changelog := Prepend("", first, Links{})
+	assert.Equal(t, Header+"\n"+first.Markdown(Links{}), changelog)
+
+	// Re-running with overlapping commits only adds the new ones
+	second := Build("v2.0.0", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), commits, featureOf, nil)
+	updated := Prepend(changelog, second, Links{})
+	expected := Header + "\n" + `## [v2.0.0] - 2024-05-01
+
+### Added
+
+- **BREAKING** **API**: drop v1 endpoints (ddd4440)
+- **Payment**: coupons (fff6660)
+
+` + first.Markdown(Links{})
+	assert.Equal(t, expected, updated)
+
+	assert.Equal(t, updated, Prepend(updated, second, Links{}), "nothing new to add")
+
+	custom := Prepend("# Release notes\n", first, Links{})
+	assert.Equal(t, "# Release notes\n\n"+first.Markdown(Links{}), custom)
+
+	linked := Prepend(Prepend("", first, testLinks), second, testLinks)
+	assert.Equal(t, linked, Prepend(linked, second, testLinks), "linked hashes are listed too")
+}
+
+func TestPrependIgnoresHexWords(t *testing.T) {
+	commits := testCommits()
+	release := Build("v1.0.0", time.Time{}, commits[:2], nil, nil)
+
+	var hashes []string
+	for _, section := range release.Sections {
+		for _, entry := range section.Entries {
+			hashes = append(hashes, entry.Hash[:7])
+		}
+	}
+	// Words that look like hashes, in the description or between parentheses
+	existing := Header + "\n## [v0.9.0]\n\n### Fixed\n\n" +
+		"- remove deadbeef placeholder, facade1 and " + hashes[0] + " (1234567)\n" +
+		"- drop the (cafebabe) magic number, see " + hashes[1] + " ([7654321](https://example.com))\n"
+
+	updated := Prepend(existing, release, Links{})
+	assert.NotEqual(t, existing, updated)
+	for _, hash := range hashes {
+		assert.Contains(t, updated, "("+hash+")", "%s is only mentioned in a description", hash)
+	}
+}
+
+func TestPrependMergesSameVersion(t *testing.T) {
+	commits := testCommits()
+	featureOf := testFeatures(commits)
+	older := Prepend("", Build("v1.0.0", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), commits[:1], featureOf, nil), Links{})
+
+	// Unreleased is regenerated as commits land on top of the release
+	changelog := Prepend(older, Build(Unreleased, time.Time{}, commits[1:4], featureOf, nil), Links{})
+	changelog = Prepend(changelog, Build(Unreleased, time.Time{}, commits[1:], featureOf, nil), Links{})
+
+	expected := Header + "\n" + `## [Unreleased]
+
+### Added
+
+- **BREAKING** **API**: drop v1 endpoints (ddd4440)
+- **Payment**: coupons (fff6660)
+
+### Fixed
+
+- **Payment**: round totals (bbb2220)
+
+## [v1.0.0] - 2024-04-01
+
+### Added
+
+- **Authentication**: add sso (#12) (aaa1110)
+`
+	assert.Equal(t, expected, changelog)
+
+	// Sections missing from the existing version are added in order
+	existing := Header + "\n## [Unreleased]\n\n### Fixed\n\n- **Payment**: round totals (bbb2220)\n"
+	merged := Prepend(existing, Build(Unreleased, time.Time{}, commits, featureOf, nil), Links{})
+	assert.Equal(t, Header+"\n"+`## [Unreleased]
+
+### Added
+
+- **BREAKING** **API**: drop v1 endpoints (ddd4440)
+- **Authentication**: add sso (#12) (aaa1110)
+- **Payment**: coupons (fff6660)
+
+### Fixed
+
+- **Payment**: round totals (bbb2220)
+`, merged)
+}
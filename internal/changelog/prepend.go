@@ -0,0 +1,176 @@
+package changelog
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Header starts a new changelog file
+const Header = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+`
+
+// listedHash matches the commit an entry ends with, as Markdown writes it: "(abc1234)" or
+// "([abc1234](url))"
+var listedHash = regexp.MustCompile(`\(\[?([0-9a-f]{7,40})(?:\]\([^()\s]*\))?\)`)
+
+// Prepend inserts the release above the newest version of an existing changelog, which
+// may be empty. Entries whose commits are already listed are left out, and the changelog
+// is returned unchanged when nothing new remains. When the changelog already has a
+// heading for the release's version, e.g. Unreleased, the entries are merged into it
+func Prepend(existing string, release Release, links Links) string {
+	var listed []string
+	for _, line := range strings.Split(existing, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "- ") {
+			continue
+		}
+		// The description comes first and may itself hold parentheses, so take the last
+		if matches := listedHash.FindAllStringSubmatch(line, -1); len(matches) > 0 {
+			listed = append(listed, matches[len(matches)-1][1])
+		}
+	}
+	release = release.Without(func(hash string) bool {
+		for _, prefix := range listed {
+			if strings.HasPrefix(hash, prefix) {
+				return true
+			}
+		}
+		return false
+	})
+	if release.Empty() {
+		return existing
+	}
+
+	block := release.Markdown(links)
+	if strings.TrimSpace(existing) == "" {
+		return Header + "\n" + block
+	}
+
+	// Insert before the first version heading, or after everything else
+	lines := strings.SplitAfter(existing, "\n")
+	offset, first := 0, -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			offset += len(line)
+			continue
+		}
+		if first < 0 {
+			first = offset
+		}
+		if strings.EqualFold(headingVersion(line), release.Version) {
+			end := offset + len(line)
+			for _, next := range lines[i+1:] {
+				if strings.HasPrefix(next, "## ") {
+					break
+				}
+				end += len(next)
+			}
+			return existing[:offset] + merge(existing[offset:end], release, links) + existing[end:]
+		}
+		offset += len(line)
+	}
+	if first >= 0 {
+		return existing[:first] + block + "\n" + existing[first:]
+	}
+	if !strings.HasSuffix(existing, "\n") {
+		existing += "\n"
+	}
+	return existing + "\n" + block
+}
+
+// headingVersion returns the version of a "## [1.0.0] - 2024-05-01" heading
+func headingVersion(line string) string {
+	heading := strings.TrimSpace(strings.TrimPrefix(line, "## "))
+	if strings.HasPrefix(heading, "[") {
+		if end := strings.Index(heading, "]"); end > 0 {
+			return heading[1:end]
+		}
+	}
+	if fields := strings.Fields(heading); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// merge adds the entries of a release to an existing version block, at the end of the
+// sections with the same title. Missing sections are added in Keep a Changelog order
+func merge(block string, release Release, links Links) string {
+	if !strings.HasSuffix(block, "\n") {
+		block += "\n"
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(block, "\n"), "\n")
+	lines[len(lines)-1] += "\n"
+
+	for _, section := range release.Sections {
+		entries := section.markdown(links)
+		start := -1
+		for i, line := range lines {
+			if strings.TrimSpace(line) == "### "+section.Title {
+				start = i
+				break
+			}
+		}
+
+		if start < 0 {
+			// Before the first section that comes later, or at the end
+			at := len(lines)
+			for i, line := range lines {
+				if strings.HasPrefix(line, "### ") && order(strings.TrimSpace(line[4:])) > order(section.Title) {
+					at = i
+					break
+				}
+			}
+			if at == len(lines) {
+				at = lastContent(lines, 0, at) + 1
+				lines = insert(lines, at, "\n### "+section.Title+"\n\n"+entries)
+			} else {
+				lines = insert(lines, at, "### "+section.Title+"\n\n"+entries+"\n")
+			}
+			continue
+		}
+
+		end := len(lines)
+		for i := start + 1; i < len(lines); i++ {
+			if strings.HasPrefix(lines[i], "### ") {
+				end = i
+				break
+			}
+		}
+		at := lastContent(lines, start, end)
+		if at == start {
+			entries = "\n" + entries
+		}
+		lines = insert(lines, at+1, entries)
+	}
+	return strings.Join(lines, "")
+}
+
+// order is the position of a section title in Keep a Changelog order, unknown titles last
+func order(title string) int {
+	for i, known := range sectionOrder {
+		if strings.EqualFold(known, title) {
+			return i
+		}
+	}
+	return len(sectionOrder)
+}
+
+// lastContent returns the last non-blank line in lines[from:to], or from
+func lastContent(lines []string, from, to int) int {
+	for i := to - 1; i > from; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			return i
+		}
+	}
+	return from
+}
+
+func insert(lines []string, at int, text string) []string {
+	lines = append(lines, "")
+	copy(lines[at+1:], lines[at:])
+	lines[at] = text
+	return lines
+}
@@ -0,0 +1,301 @@
+// Package console renders the feature analysis as a plain-text report for the terminal.
+// Output is deterministic: features by activity, owners by share, everything else by date
+package console
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"git-history-onboarding/internal/analysis/features"
+	"git-history-onboarding/internal/analysis/szz"
+	"git-history-onboarding/internal/display"
+	"git-history-onboarding/internal/models"
+)
+
+// Options controls what the report shows
+type Options struct {
+	All       bool // include features without commits
+	Color     bool // highlight with ANSI escape codes
+	BugTraces bool // include the lifetime and authors of traced bugs
+}
+
+// UseColor reports whether to color output written to out: always, never, or on a
+// terminal when NO_COLOR is unset and TERM is not "dumb"
+func UseColor(mode string, out *os.File) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+			return false, nil
+		}
+		info, err := out.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	default:
+		return false, fmt.Errorf("unknown color mode %q, expected auto, always or never", mode)
+	}
+}
+
+// Write prints a section per feature, the most active first
+func Write(w io.Writer, featureAnalysis map[string]*models.Feature, opts Options) error {
+	p := &printer{w: bufio.NewWriter(w), color: opts.Color}
+
+	list := byActivity(featureAnalysis)
+	shown := list[:0:0]
+	for _, feature := range list {
+		if opts.All || len(feature.Commits) > 0 {
+			shown = append(shown, feature)
+		}
+	}
+
+	p.printf("%s (%s", p.style(bold, "Feature Analysis"), display.Plural(len(shown), "feature"))
+	if hidden := len(list) - len(shown); hidden > 0 {
+		p.printf(", %d without commits hidden, use --all to show", hidden)
+	}
+	p.printf(")\n")
+
+	for _, feature := range shown {
+		p.feature(feature, opts)
+	}
+	return p.w.Flush()
+}
+
+// byActivity orders features with the most commits first, then the most recently
+// updated, then by name
+func byActivity(featureAnalysis map[string]*models.Feature) []*models.Feature {
+	list := make([]*models.Feature, 0, len(featureAnalysis))
+	for _, feature := range featureAnalysis {
+		list = append(list, feature)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if len(a.Commits) != len(b.Commits) {
+			return len(a.Commits) > len(b.Commits)
+		}
+		if !a.LastUpdated.Equal(b.LastUpdated) {
+			return a.LastUpdated.After(b.LastUpdated)
+		}
+		return a.Name < b.Name
+	})
+	return list
+}
+
+// owner is a person's share of a feature
+type owner struct {
+	Email  string
+	Share  float64
+	Backup bool
+}
+
+// owners lists primary then backup owners, each by share, largest first
+func owners(feature *models.Feature) []owner {
+	var list []owner
+	for _, role := range []struct {
+		shares map[string]float64
+		backup bool
+	}{{feature.Owners, false}, {feature.BackupOwners, true}} {
+		start := len(list)
+		for email, share := range role.shares {
+			list = append(list, owner{Email: email, Share: share, Backup: role.backup})
+		}
+		group := list[start:]
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].Share != group[j].Share {
+				return group[i].Share > group[j].Share
+			}
+			return group[i].Email < group[j].Email
+		})
+	}
+	return list
+}
+
+// ANSI styles
+const (
+	bold   = "1"
+	dim    = "2"
+	red    = "31"
+	green  = "32"
+	yellow = "33"
+	cyan   = "36"
+)
+
+type printer struct {
+	w     *bufio.Writer
+	color bool
+}
+
+func (p *printer) printf(format string, args ...interface{}) {
+	fmt.Fprintf(p.w, format, args...)
+}
+
+func (p *printer) style(code, s string) string {
+	if !p.color || code == "" || s == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+func (p *printer) feature(feature *models.Feature, opts Options) {
+	p.printf("\n%s\n", p.style(bold+";"+cyan, feature.Name))
+
+	var details table
+	if feature.Parent != "" {
+		details.add(cell{text: "Parent"}, cell{text: feature.Parent})
+	}
+	if len(feature.Children) > 0 {
+		details.add(cell{text: "Sub-features"}, cell{text: strings.Join(feature.Children, ", ")})
+	}
+	if len(feature.Commits) > 0 {
+		details.add(cell{text: "Created"}, cell{text: display.Date(feature.CreatedAt)})
+		details.add(cell{text: "Last updated"}, cell{text: display.Date(feature.LastUpdated)})
+	}
+	details.add(cell{text: "Commits"}, cell{text: fmt.Sprint(len(feature.Commits))})
+	bugs := cell{text: fmt.Sprint(len(feature.Bugs))}
+	if len(feature.Bugs) > 0 {
+		bugs.style = yellow
+	}
+	details.add(cell{text: "Bugs"}, bugs)
+	for i := range details {
+		details[i][0].style = dim
+	}
+	p.table("  ", details)
+
+	if list := owners(feature); len(list) > 0 {
+		p.heading("Owners")
+		var rows table
+		for _, owner := range list {
+			role, style := "primary", green
+			if owner.Backup {
+				role, style = "backup", ""
+			}
+			rows.add(
+				cell{text: owner.Email},
+				cell{text: fmt.Sprintf("%.1f%%", owner.Share*100), style: style, right: true},
+				cell{text: role, style: dim},
+			)
+		}
+		p.table("    ", rows)
+	}
+
+	if len(feature.Bugs) > 0 {
+		p.heading("Bug history")
+		var rows table
+		for _, bug := range bugHistory(feature) {
+			rows.add(cell{text: display.Date(bug.FixedAt), style: dim}, cell{text: bug.AuthorEmail}, cell{text: display.FirstLine(bug.Description)})
+			for _, ref := range bug.Issues {
+				rows.add(cell{}, cell{text: "issue", style: dim}, cell{text: strings.TrimSpace(fmt.Sprintf("%s %s %s", ref.Tracker, ref.ID, ref.Title))})
+			}
+			for _, origin := range bug.IntroducedBy {
+				rows.add(cell{}, cell{text: "introduced by", style: dim}, cell{
+					text: fmt.Sprintf("%s (%s, %s)", display.ShortHash(origin.CommitHash), origin.AuthorEmail, display.Date(origin.Date)),
+				})
+			}
+		}
+		p.table("    ", rows)
+	}
+
+	if len(feature.BreakingChanges) > 0 {
+		p.heading("Breaking changes")
+		var rows table
+		for _, change := range features.BreakingTimeline(feature) {
+			rows.add(cell{text: display.Date(change.Date), style: dim}, cell{text: change.AuthorEmail}, cell{text: display.FirstLine(change.Description), style: red})
+		}
+		p.table("    ", rows)
+	}
+
+	if len(feature.Reverts) > 0 {
+		p.heading(fmt.Sprintf("Reverts: %d, %.1f%% revert rate", len(feature.Reverts), features.RevertRate(feature)*100))
+		reverts := append([]models.Revert(nil), feature.Reverts...)
+		sort.SliceStable(reverts, func(i, j int) bool {
+			return reverts[i].Date.Before(reverts[j].Date)
+		})
+		var rows table
+		for _, revert := range reverts {
+			rows.add(cell{text: display.Date(revert.Date), style: dim}, cell{text: revert.AuthorEmail}, cell{text: display.FirstLine(revert.Description)})
+		}
+		p.table("    ", rows)
+	}
+
+	if opts.BugTraces {
+		stats := szz.Stats(feature)
+		if stats.TracedBugs > 0 {
+			p.heading(fmt.Sprintf("Traced bugs: %d, mean lifetime %.1f days", stats.TracedBugs, stats.MeanLifetime.Hours()/24))
+			var rows table
+			for _, author := range stats.Authors {
+				rows.add(cell{text: author.AuthorEmail}, cell{text: display.Plural(author.Bugs, "bug"), right: true})
+			}
+			p.table("    ", rows)
+		}
+	}
+}
+
+func (p *printer) heading(title string) {
+	p.printf("\n  %s\n", p.style(bold, title))
+}
+
+// table prints rows with aligned columns. Widths are measured before styling, so
+// escape codes never shift the columns
+func (p *printer) table(indent string, rows table) {
+	var widths []int
+	for _, row := range rows {
+		for i, c := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := utf8.RuneCountInString(c.text); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	for _, row := range rows {
+		var line strings.Builder
+		line.WriteString(indent)
+		for i, c := range row {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c.text))
+			last := i == len(row)-1
+			switch {
+			case c.right:
+				line.WriteString(pad + p.style(c.style, c.text))
+			case last:
+				line.WriteString(p.style(c.style, c.text))
+			default:
+				line.WriteString(p.style(c.style, c.text) + pad)
+			}
+			if !last {
+				line.WriteString("  ")
+			}
+		}
+		p.printf("%s\n", strings.TrimRight(line.String(), " "))
+	}
+}
+
+type cell struct {
+	text  string
+	style string // ANSI style, "" for none
+	right bool   // right-aligned, for numbers
+}
+
+type table [][]cell
+
+func (t *table) add(cells ...cell) {
+	*t = append(*t, cells)
+}
+
+// bugHistory returns a feature's bugs, oldest fix first
+func bugHistory(feature *models.Feature) []models.Bug {
+	bugs := append([]models.Bug(nil), feature.Bugs...)
+	sort.SliceStable(bugs, func(i, j int) bool {
+		if !bugs[i].FixedAt.Equal(bugs[j].FixedAt) {
+			return bugs[i].FixedAt.Before(bugs[j].FixedAt)
+		}
+		return bugs[i].CommitHash < bugs[j].CommitHash
+	})
+	return bugs
+}
@@ -0,0 +1,202 @@
+package console
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+	"git-history-onboarding/internal/report"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func createTestCommit(hash string, message string, email string, when time.Time) git.CommitInfo {
+	return git.CommitInfo{
+		Commit: &object.Commit{
+			Hash:    plumbing.NewHash(hash),
+			Author:  object.Signature{Name: email, Email: email, When: when},
+			Message: message,
+		},
+	}
+}
+
+func testFeatures() map[string]*models.Feature {
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	commits := []git.CommitInfo{
+		createTestCommit("eeee05", "revert: sessions", "jane@example.com", start.AddDate(0, 4, 0)),
+		createTestCommit("dddd04", "feat(search)!: facets", "zoë@example.com", start.AddDate(0, 3, 0)),
+		createTestCommit("cccc03", "fix(auth): session expiry", "jane@example.com", start.AddDate(0, 2, 0)),
+		createTestCommit("bbbb02", "feat(auth): sessions", "john@example.com", start.AddDate(0, 0, 1)),
+		createTestCommit("aaaa01", "feat(auth): login", "john@example.com", start),
+	}
+	hash := func(i int) string { return commits[i].Commit.Hash.String() }
+
+	return map[string]*models.Feature{
+		"Authentication": {
+			Name:     "Authentication",
+			Parent:   "Security",
+			Children: []string{"OAuth", "Sessions"},
+			Commits:  []git.CommitInfo{commits[0], commits[2], commits[3], commits[4]},
+			Owners: map[string]float64{
+				"john@example.com":   0.5,
+				"alice@example.com":  0.25,
+				"jane@example.com":   0.25,
+				"xavier@example.com": 0.5,
+			},
+			BackupOwners: map[string]float64{"bob@example.com": 0.1},
+			CreatedAt:    start,
+			LastUpdated:  start.AddDate(0, 4, 0),
+			Bugs: []models.Bug{
+				{
+					CommitHash:  hash(2),
+					Description: "fix(auth): session expiry\n\nSessions never expired",
+					AuthorEmail: "jane@example.com",
+					FixedAt:     start.AddDate(0, 2, 0),
+					IntroducedBy: []models.BugOrigin{
+						{CommitHash: hash(3), AuthorEmail: "john@example.com", Date: start.AddDate(0, 0, 1)},
+					},
+					IntroducedAt: start.AddDate(0, 0, 1),
+					Issues:       []models.IssueRef{{Tracker: "github", ID: "42", Title: "Logged in forever"}},
+				},
+			},
+			Reverts: []models.Revert{
+				{CommitHash: hash(0), RevertedHash: hash(3), Description: "feat(auth): sessions", AuthorEmail: "jane@example.com", Date: start.AddDate(0, 4, 0)},
+			},
+		},
+		"Search": {
+			Name:         "Search",
+			Commits:      commits[1:2],
+			Owners:       map[string]float64{"zoë@example.com": 1},
+			BackupOwners: map[string]float64{},
+			CreatedAt:    start.AddDate(0, 3, 0),
+			LastUpdated:  start.AddDate(0, 3, 0),
+			BreakingChanges: []models.BreakingChange{
+				{CommitHash: hash(1), Description: "facets", AuthorEmail: "zoë@example.com", Date: start.AddDate(0, 3, 0)},
+			},
+		},
+		"Billing": {
+			Name:         "Billing",
+			Owners:       map[string]float64{},
+			BackupOwners: map[string]float64{},
+		},
+		"Admin": {
+			Name:         "Admin",
+			Owners:       map[string]float64{},
+			BackupOwners: map[string]float64{},
+		},
+	}
+}
+
+func TestWrite(t *testing.T) {
+	for _, test := range []struct {
+		golden string
+		opts   Options
+	}{
+		{"default.golden", Options{}},
+		{"all.golden", Options{All: true, BugTraces: true}},
+		{"color.golden", Options{Color: true, BugTraces: true}},
+	} {
+		t.Run(test.golden, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, Write(&buf, testFeatures(), test.opts))
+
+			path := filepath.Join("testdata", test.golden)
+			if *update {
+				assert.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+			}
+			want, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, string(want), buf.String())
+		})
+	}
+}
+
+func TestWriteDiff(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	diff := report.Diff{
+		From:            report.Snapshot{Head: "aaaa01aaaa01", Commits: 10, LastCommit: start},
+		To:              report.Snapshot{Head: "bbbb02bbbb02", Commits: 1, LastCommit: start.AddDate(0, 3, 0)},
+		FeaturesAdded:   []string{"Search"},
+		FeaturesRemoved: []string{"Legacy"},
+		OwnerChanges: []report.OwnerChange{
+			{Feature: "Authentication", Email: "jane@example.com", Kind: report.OwnerShifted, OldShare: 0.1, NewShare: 0.35, OldRole: "backup", NewRole: "primary"},
+			{Feature: "Authentication", Email: "amy@example.com", Kind: report.OwnerRemoved, OldShare: 0.3, OldRole: "primary"},
+			{Feature: "Payments", Email: "zoe@example.com", Kind: report.OwnerAdded, NewShare: 0.15, NewRole: "backup"},
+		},
+		NewBugs: []report.FeatureBug{
+			{Feature: "Search", Bug: report.Bug{Description: "fix(search): empty query", AuthorEmail: "zoe@example.com", FixedAt: start.AddDate(0, 1, 0)}},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteDiff(&buf, diff, false))
+	path := filepath.Join("testdata", "diff.golden")
+	if *update {
+		assert.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+	}
+	want, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), buf.String())
+
+	buf.Reset()
+	assert.NoError(t, WriteDiff(&buf, report.Diff{From: diff.From, To: diff.From}, false))
+	assert.Equal(t, "Changes from aaaa01a (2024-01-01, 10 commits) to aaaa01a (2024-01-01, 10 commits)\n\nNo changes in features, owners or bugs\n", buf.String())
+}
+
+func TestWriteIsDeterministic(t *testing.T) {
+	var first bytes.Buffer
+	assert.NoError(t, Write(&first, testFeatures(), Options{All: true}))
+	for i := 0; i < 20; i++ {
+		var again bytes.Buffer
+		assert.NoError(t, Write(&again, testFeatures(), Options{All: true}))
+		assert.Equal(t, first.String(), again.String())
+	}
+}
+
+func TestByActivity(t *testing.T) {
+	var names []string
+	for _, feature := range byActivity(testFeatures()) {
+		names = append(names, feature.Name)
+	}
+	assert.Equal(t, []string{"Authentication", "Search", "Admin", "Billing"}, names,
+		"most commits first, empty features by name")
+}
+
+func TestOwners(t *testing.T) {
+	assert.Equal(t, []owner{
+		{Email: "john@example.com", Share: 0.5},
+		{Email: "xavier@example.com", Share: 0.5},
+		{Email: "alice@example.com", Share: 0.25},
+		{Email: "jane@example.com", Share: 0.25},
+		{Email: "bob@example.com", Share: 0.1, Backup: true},
+	}, owners(testFeatures()["Authentication"]))
+}
+
+func TestUseColor(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "out")
+	assert.NoError(t, err)
+	defer file.Close()
+
+	color, err := UseColor("always", file)
+	assert.NoError(t, err)
+	assert.True(t, color)
+
+	color, err = UseColor("auto", file)
+	assert.NoError(t, err)
+	assert.False(t, color, "a regular file is not a terminal")
+
+	color, err = UseColor("never", file)
+	assert.NoError(t, err)
+	assert.False(t, color)
+
+	_, err = UseColor("sometimes", file)
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package console
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"git-history-onboarding/internal/display"
+	"git-history-onboarding/internal/report"
+)
+
+// WriteDiff prints the changes between two reports: features gained and lost, owner
+// changes and new bugs
+func WriteDiff(w io.Writer, diff report.Diff, color bool) error {
+	p := &printer{w: bufio.NewWriter(w), color: color}
+
+	p.printf("%s from %s to %s\n", p.style(bold, "Changes"), snapshot(diff.From), snapshot(diff.To))
+	if diff.Empty() {
+		p.printf("\nNo changes in features, owners or bugs\n")
+		return p.w.Flush()
+	}
+
+	if len(diff.FeaturesAdded) > 0 {
+		p.heading(fmt.Sprintf("Features added (%d)", len(diff.FeaturesAdded)))
+		for _, name := range diff.FeaturesAdded {
+			p.printf("    %s %s\n", p.style(green, "+"), name)
+		}
+	}
+	if len(diff.FeaturesRemoved) > 0 {
+		p.heading(fmt.Sprintf("Features removed (%d)", len(diff.FeaturesRemoved)))
+		for _, name := range diff.FeaturesRemoved {
+			p.printf("    %s %s\n", p.style(red, "-"), name)
+		}
+	}
+
+	if len(diff.OwnerChanges) > 0 {
+		p.heading(fmt.Sprintf("Owner changes (%d)", len(diff.OwnerChanges)))
+		var rows table
+		for _, change := range diff.OwnerChanges {
+			shift, style := fmt.Sprintf("%+.1f pts", change.Shift()*100), green
+			if change.Shift() < 0 {
+				style = red
+			}
+			rows.add(
+				cell{text: change.Feature},
+				cell{text: change.Email},
+				cell{text: change.Kind, style: dim},
+				cell{text: ownerShare(change.OldShare, change.OldRole), right: true},
+				cell{text: "->", style: dim},
+				cell{text: ownerShare(change.NewShare, change.NewRole)},
+				cell{text: shift, style: style, right: true},
+			)
+		}
+		p.table("    ", rows)
+	}
+
+	if len(diff.NewBugs) > 0 {
+		p.heading(fmt.Sprintf("New bugs (%d)", len(diff.NewBugs)))
+		var rows table
+		for _, bug := range diff.NewBugs {
+			rows.add(
+				cell{text: bug.Feature},
+				cell{text: display.Date(bug.Bug.FixedAt), style: dim},
+				cell{text: bug.Bug.AuthorEmail},
+				cell{text: display.FirstLine(bug.Bug.Description), style: yellow},
+			)
+		}
+		p.table("    ", rows)
+	}
+	return p.w.Flush()
+}
+
+func snapshot(s report.Snapshot) string {
+	if s.Head == "" {
+		return "an empty history"
+	}
+	return fmt.Sprintf("%s (%s, %s)", display.ShortHash(s.Head), display.Date(s.LastCommit), display.Plural(s.Commits, "commit"))
+}
+
+// ownerShare formats a share with its role, or "-" when not an owner
+func ownerShare(share float64, role string) string {
+	if role == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%% %s", share*100, role)
+}
@@ -0,0 +1,56 @@
+// Package display formats commit hashes, dates and counts for people to read, so every
+// output format shows them the same way
+package display
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateLayout shows dates as YYYY-MM-DD
+const DateLayout = "2006-01-02"
+
+// ShortHashLength is how many characters of a commit hash are shown
+const ShortHashLength = 7
+
+// ShortHash abbreviates a commit hash
+func ShortHash(hash string) string {
+	if len(hash) > ShortHashLength {
+		return hash[:ShortHashLength]
+	}
+	return hash
+}
+
+// Date formats a time as YYYY-MM-DD, or "unknown" when it is zero
+func Date(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Format(DateLayout)
+}
+
+// OptionalDate is like Date, and also "unknown" for nil
+func OptionalDate(t *time.Time) string {
+	if t == nil {
+		return "unknown"
+	}
+	return Date(*t)
+}
+
+// Plural counts a noun, e.g. "1 commit", "2 commits" or "3 fixes"
+func Plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	if strings.HasSuffix(noun, "x") {
+		return fmt.Sprintf("%d %ses", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// FirstLine returns the subject of a commit message
+func FirstLine(message string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,32 @@
+package display
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShortHash(t *testing.T) {
+	assert.Equal(t, "abc1234", ShortHash("abc1234def5678"))
+	assert.Equal(t, "abc", ShortHash("abc"))
+}
+
+func TestDate(t *testing.T) {
+	day := time.Date(2024, 5, 1, 23, 30, 0, 0, time.UTC)
+	assert.Equal(t, "2024-05-01", Date(day))
+	assert.Equal(t, "unknown", Date(time.Time{}))
+	assert.Equal(t, "2024-05-01", OptionalDate(&day))
+	assert.Equal(t, "unknown", OptionalDate(nil))
+}
+
+func TestPlural(t *testing.T) {
+	assert.Equal(t, "1 commit", Plural(1, "commit"))
+	assert.Equal(t, "0 commits", Plural(0, "commit"))
+	assert.Equal(t, "2 bug fixes", Plural(2, "bug fix"))
+}
+
+func TestFirstLine(t *testing.T) {
+	assert.Equal(t, "fix(auth): login", FirstLine("\n  fix(auth): login  \n\nBody"))
+	assert.Equal(t, "", FirstLine(""))
+}
@@ -0,0 +1,216 @@
+// Package docs generates Markdown onboarding guides from analysis results
+package docs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"git-history-onboarding/internal/analysis/timeline"
+	"git-history-onboarding/internal/display"
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+	"git-history-onboarding/internal/report"
+)
+
+// RecentWindow is how far back from the newest commit of the repository a feature's
+// recent activity is counted
+const RecentWindow = 90 * 24 * time.Hour
+
+// Lengths of the lists on a feature page
+const (
+	recentLimit  = 5
+	keyFileLimit = 10
+	notableLimit = 8
+	askOwners    = 2
+)
+
+// Guide is the data the templates render
+type Guide struct {
+	Repository report.Repository
+	Features   []Page // by name
+	CommitURL  string // format for commit links, e.g. "https://github.com/acme/shop/commit/%s"
+}
+
+// Page is the onboarding page of a feature
+type Page struct {
+	report.Feature
+	Slug           string    // file name without extension, e.g. "payments-refunds"
+	WhoToAsk       []Contact // most relevant first
+	RecentCommits  int       // commits in the RecentWindow before the newest commit of the repository
+	RecentActivity []Commit  // newest first
+	KeyFiles       []File    // most changed first
+	NotableCommits []Commit  // oldest first
+}
+
+// Contact is someone to ask about a feature, and why
+type Contact struct {
+	Email  string
+	Reason string
+}
+
+// Commit summarizes a commit for a page
+type Commit struct {
+	Hash        string
+	Subject     string
+	AuthorEmail string
+	Date        time.Time
+	Files       int
+}
+
+// File is a file of a feature and how many of its commits changed it
+type File struct {
+	Path    string
+	Commits int
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slug turns a feature name into a file name, e.g. "Payments > Refunds" into "payments-refunds".
+// Names without letters or digits become "untitled". Different names may share a slug;
+// UniqueSlug tells them apart
+func Slug(name string) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		return "untitled"
+	}
+	return slug
+}
+
+// UniqueSlug returns slug, numbered from 2 when it is already taken, and marks the
+// result as taken
+func UniqueSlug(slug string, taken map[string]bool) string {
+	unique := slug
+	for n := 2; taken[unique]; n++ {
+		unique = fmt.Sprintf("%s-%d", slug, n)
+	}
+	taken[unique] = true
+	return unique
+}
+
+// RecentWindowText describes the RecentWindow, e.g. "90 days"
+func RecentWindowText() string {
+	return display.Plural(int(RecentWindow/(24*time.Hour)), "day")
+}
+
+// Build collects the guide of the features that have commits
+func Build(url string, commits []git.CommitInfo, featureAnalysis map[string]*models.Feature, commitURL string) Guide {
+	rep := report.New(url, commits, featureAnalysis)
+	guide := Guide{Repository: rep.Repository, CommitURL: commitURL}
+	// "Auth/API" and "Auth API" share a slug; number the later ones. "index" is the
+	// index page
+	taken := map[string]bool{"index": true}
+	for _, feature := range rep.Features {
+		if len(feature.Commits) == 0 {
+			continue
+		}
+		page := newPage(feature, featureAnalysis[feature.Name], rep.Repository.LastCommit)
+		page.Slug = UniqueSlug(page.Slug, taken)
+		guide.Features = append(guide.Features, page)
+	}
+	return guide
+}
+
+func newPage(feature report.Feature, source *models.Feature, now time.Time) Page {
+	page := Page{Feature: feature, Slug: Slug(feature.Name)}
+
+	commits := make([]git.CommitInfo, len(source.Commits))
+	copy(commits, source.Commits)
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Commit.Author.When.After(commits[j].Commit.Author.When)
+	})
+
+	changes := make(map[string]int)
+	for i, commit := range commits {
+		if i < recentLimit {
+			page.RecentActivity = append(page.RecentActivity, newCommit(commit))
+		}
+		if now.Sub(commit.Commit.Author.When) <= RecentWindow {
+			page.RecentCommits++
+		}
+		for _, file := range commit.Files {
+			changes[file]++
+		}
+	}
+	for path, count := range changes {
+		page.KeyFiles = append(page.KeyFiles, File{Path: path, Commits: count})
+	}
+	sort.Slice(page.KeyFiles, func(i, j int) bool {
+		if page.KeyFiles[i].Commits != page.KeyFiles[j].Commits {
+			return page.KeyFiles[i].Commits > page.KeyFiles[j].Commits
+		}
+		return page.KeyFiles[i].Path < page.KeyFiles[j].Path
+	})
+	if len(page.KeyFiles) > keyFileLimit {
+		page.KeyFiles = page.KeyFiles[:keyFileLimit]
+	}
+
+	page.NotableCommits = notableCommits(source)
+	page.WhoToAsk = whoToAsk(feature, commits)
+	return page
+}
+
+// notableCommits are the key commits of each phase of the feature's story
+func notableCommits(feature *models.Feature) []Commit {
+	var notable []Commit
+	for _, phase := range timeline.Tell(feature, timeline.StoryOptions{}).Phases {
+		for _, commit := range phase.KeyCommits {
+			notable = append(notable, newCommit(commit))
+		}
+	}
+	// Keep the newest phases when the history is long
+	if len(notable) > notableLimit {
+		notable = notable[len(notable)-notableLimit:]
+	}
+	return notable
+}
+
+// whoToAsk lists the largest owners, the author of the latest change and the author
+// of the most bug fixes, each once
+func whoToAsk(feature report.Feature, commits []git.CommitInfo) []Contact {
+	var contacts []Contact
+	seen := make(map[string]bool)
+	add := func(email, reason string) {
+		if email != "" && !seen[email] {
+			seen[email] = true
+			contacts = append(contacts, Contact{Email: email, Reason: reason})
+		}
+	}
+
+	for i, owner := range feature.Owners {
+		if i == askOwners {
+			break
+		}
+		add(owner.Email, fmt.Sprintf("owns %.0f%% of the feature", owner.Share*100))
+	}
+	if len(commits) > 0 {
+		latest := commits[0].Commit
+		add(latest.Author.Email, "made the latest change on "+display.Date(latest.Author.When))
+	}
+
+	fixes := make(map[string]int)
+	fixer := ""
+	for _, bug := range feature.Bugs {
+		fixes[bug.AuthorEmail]++
+		if fixer == "" || fixes[bug.AuthorEmail] > fixes[fixer] || fixes[bug.AuthorEmail] == fixes[fixer] && bug.AuthorEmail < fixer {
+			fixer = bug.AuthorEmail
+		}
+	}
+	if fixer != "" {
+		add(fixer, fmt.Sprintf("fixed %d of its bugs", fixes[fixer]))
+	}
+	return contacts
+}
+
+func newCommit(commit git.CommitInfo) Commit {
+	subject, _, _ := strings.Cut(commit.Commit.Message, "\n")
+	return Commit{
+		Hash:        commit.Commit.Hash.String(),
+		Subject:     strings.TrimSpace(subject),
+		AuthorEmail: commit.Commit.Author.Email,
+		Date:        commit.Commit.Author.When,
+		Files:       len(commit.Files),
+	}
+}
@@ -0,0 +1,182 @@
+package docs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestCommit(hash string, message string, email string, when time.Time, files []string) git.CommitInfo {
+	return git.CommitInfo{
+		Commit: &object.Commit{
+			Hash:    plumbing.NewHash(hash),
+			Author:  object.Signature{Name: email, Email: email, When: when},
+			Message: message,
+		},
+		Files: files,
+	}
+}
+
+func testGuide() (Guide, []git.CommitInfo) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	commits := []git.CommitInfo{
+		createTestCommit("dddd04", "fix(auth): token refresh\n\nFixes #7", "jane@example.com", start.Add(200*day), []string{"auth/token.go"}),
+		createTestCommit("cccc03", "fix(auth): session expiry", "jane@example.com", start.Add(150*day), []string{"auth/session.go", "auth/token.go"}),
+		createTestCommit("bbbb02", "feat(auth): sessions", "john@example.com", start.Add(day), []string{"auth/session.go"}),
+		createTestCommit("aaaa01", "feat(auth): login", "john@example.com", start, []string{"auth/login.go", "auth/session.go"}),
+	}
+	featureAnalysis := map[string]*models.Feature{
+		"Payments > Refunds": {Name: "Payments > Refunds"},
+		"Authentication": {
+			Name:         "Authentication",
+			Commits:      commits,
+			Owners:       map[string]float64{"john@example.com": 0.6},
+			BackupOwners: map[string]float64{"jane@example.com": 0.4},
+			CreatedAt:    start,
+			LastUpdated:  start.Add(200 * day),
+			Bugs: []models.Bug{
+				{CommitHash: commits[1].Commit.Hash.String(), Description: "fix(auth): session expiry", AuthorEmail: "jane@example.com", FixedAt: start.Add(150 * day)},
+				{CommitHash: commits[0].Commit.Hash.String(), Description: "fix(auth): token refresh\n\nFixes #7", AuthorEmail: "jane@example.com", FixedAt: start.Add(200 * day),
+					Issues: []models.IssueRef{{Tracker: "github", ID: "7", Title: "Tokens expire early"}}},
+			},
+		},
+	}
+	return Build("https://github.com/acme/shop", commits, featureAnalysis, "https://github.com/acme/shop/commit/%s"), commits
+}
+
+func TestSlug(t *testing.T) {
+	assert.Equal(t, "payments-refunds", Slug("Payments > Refunds"))
+	assert.Equal(t, "api", Slug(" API "))
+	assert.Equal(t, "untitled", Slug("?!"))
+
+	taken := make(map[string]bool)
+	assert.Equal(t, "auth-api", UniqueSlug("auth-api", taken))
+	assert.Equal(t, "auth-api-2", UniqueSlug("auth-api", taken))
+	assert.Equal(t, "auth-api-3", UniqueSlug("auth-api", taken))
+}
+
+func TestBuildUniqueSlugs(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	featureAnalysis := make(map[string]*models.Feature)
+	var commits []git.CommitInfo
+	for i, name := range []string{"Auth/API", "Auth API", "Auth > API", "Index", "???"} {
+		commit := createTestCommit(fmt.Sprintf("aaaa%02d", i), "feat: "+name, "john@example.com", now, nil)
+		commits = append(commits, commit)
+		featureAnalysis[name] = &models.Feature{Name: name, Commits: []git.CommitInfo{commit}}
+	}
+
+	guide := Build("https://github.com/acme/shop", commits, featureAnalysis, "")
+	slugs := make(map[string]string)
+	for _, page := range guide.Features {
+		slugs[page.Name] = page.Slug
+	}
+	assert.Equal(t, map[string]string{
+		"???":        "untitled",
+		"Auth > API": "auth-api",
+		"Auth API":   "auth-api-2",
+		"Auth/API":   "auth-api-3",
+		"Index":      "index-2",
+	}, slugs, "numbered by name, and the index page keeps index.md")
+
+	renderer, err := NewRenderer("")
+	assert.NoError(t, err)
+	written, err := renderer.WritePages(guide, t.TempDir())
+	assert.NoError(t, err)
+	assert.Equal(t, 6, written, "one page per feature and the index")
+}
+
+func TestBuild(t *testing.T) {
+	guide, commits := testGuide()
+	if !assert.Len(t, guide.Features, 1, "features without commits are left out") {
+		return
+	}
+
+	page := guide.Features[0]
+	assert.Equal(t, "authentication", page.Slug)
+	assert.Equal(t, []Contact{
+		{Email: "john@example.com", Reason: "owns 60% of the feature"},
+		{Email: "jane@example.com", Reason: "made the latest change on 2024-07-19"},
+	}, page.WhoToAsk, "each person is listed once")
+	assert.Equal(t, 2, page.RecentCommits)
+	if assert.Len(t, page.RecentActivity, 4) {
+		assert.Equal(t, commits[0].Commit.Hash.String(), page.RecentActivity[0].Hash)
+		assert.Equal(t, "fix(auth): token refresh", page.RecentActivity[0].Subject)
+	}
+	assert.Equal(t, []File{
+		{Path: "auth/session.go", Commits: 3},
+		{Path: "auth/token.go", Commits: 2},
+		{Path: "auth/login.go", Commits: 1},
+	}, page.KeyFiles)
+	assert.NotEmpty(t, page.NotableCommits)
+}
+
+func TestRenderGuide(t *testing.T) {
+	guide, _ := testGuide()
+	renderer, err := NewRenderer("")
+	assert.NoError(t, err)
+
+	content, err := renderer.Guide(guide)
+	assert.NoError(t, err)
+	markdown := string(content)
+	assert.Contains(t, markdown, "# Onboarding Guide")
+	assert.Contains(t, markdown, "| [Authentication](#authentication) | john@example.com | 4 | 2 | 2024-07-19 |")
+	assert.Contains(t, markdown, "## Authentication\n")
+	assert.Contains(t, markdown, "### Who to Ask\n\n- john@example.com: owns 60% of the feature\n")
+	assert.Contains(t, markdown, "- jane@example.com (40%, backup)")
+	assert.Contains(t, markdown, "- `auth/session.go` (3 commits)")
+	assert.Contains(t, markdown, "2 commits in the 90 days before the latest commit")
+	assert.Contains(t, markdown, "- 2024-07-19 fix(auth): token refresh ([dddd040](https://github.com/acme/shop/commit/dddd04")
+	assert.Contains(t, markdown, `github 7 "Tokens expire early"`)
+	assert.NotContains(t, markdown, "Back to the index")
+}
+
+func TestRenderPages(t *testing.T) {
+	guide, _ := testGuide()
+	renderer, err := NewRenderer("")
+	assert.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "onboarding")
+	written, err := renderer.WritePages(guide, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, written)
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(index), "[Authentication](authentication.md)")
+
+	page, err := os.ReadFile(filepath.Join(dir, "authentication.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "# Authentication\n\n[Back to the index](index.md)")
+	assert.Contains(t, string(page), "\n## Key Files\n")
+}
+
+func TestRendererOverrides(t *testing.T) {
+	guide, _ := testGuide()
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, TemplateFeature),
+		[]byte("{{.Heading}} {{.Name}}: ask {{(index .WhoToAsk 0).Email}}\n"), 0644))
+
+	renderer, err := NewRenderer(dir)
+	assert.NoError(t, err)
+	content, err := renderer.Guide(guide)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "## Authentication: ask john@example.com\n", "the default guide uses the overridden feature template")
+	assert.NotContains(t, string(content), "Key Files")
+
+	_, err = NewRenderer(t.TempDir())
+	assert.Error(t, err, "a directory without templates is a mistake")
+
+	broken := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(broken, TemplateIndex), []byte("{{.Name"), 0644))
+	_, err = NewRenderer(broken)
+	assert.Error(t, err)
+}
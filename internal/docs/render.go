@@ -0,0 +1,160 @@
+package docs
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"git-history-onboarding/internal/display"
+)
+
+// Template names; a directory passed to NewRenderer may override any of them
+const (
+	TemplateGuide   = "onboarding.md.tmpl" // the single-file guide
+	TemplateIndex   = "index.md.tmpl"      // the index page of a directory of pages
+	TemplateFeature = "feature.md.tmpl"    // a feature, as a page or a guide section
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// FeatureData is what the feature template renders
+type FeatureData struct {
+	Page
+	CommitURL string
+	Heading   string // "#" on a page of its own, "##" within the guide
+	Index     string // link back to the index, "" within the guide
+}
+
+// Renderer renders guides with the default templates or overrides of them
+type Renderer struct {
+	templates *template.Template
+}
+
+var funcs = template.FuncMap{
+	"date":    formatDate,
+	"percent": func(share float64) string { return fmt.Sprintf("%.0f%%", share*100) },
+	"short":   display.ShortHash,
+	"subject": display.FirstLine,
+	"join":    strings.Join,
+	"plural":  display.Plural,
+	"window":  RecentWindowText,
+	"link":    commitLink,
+	"feature": func(guide Guide, page Page, heading string) FeatureData {
+		return FeatureData{Page: page, CommitURL: guide.CommitURL, Heading: heading}
+	},
+}
+
+// Funcs returns a copy of the functions available to the templates, for other
+// renderers to build on
+func Funcs() map[string]interface{} {
+	copied := make(map[string]interface{}, len(funcs))
+	for name, fn := range funcs {
+		copied[name] = fn
+	}
+	return copied
+}
+
+// NewRenderer parses the default templates, then the *.tmpl files of overrideDir, if
+// any, which replace the default templates of the same name
+func NewRenderer(overrideDir string) (*Renderer, error) {
+	templates, err := template.New("docs").Funcs(funcs).ParseFS(defaultTemplates, "templates/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	if overrideDir != "" {
+		overrides, err := filepath.Glob(filepath.Join(overrideDir, "*.tmpl"))
+		if err != nil {
+			return nil, err
+		}
+		if len(overrides) == 0 {
+			return nil, fmt.Errorf("no *.tmpl templates in %s", overrideDir)
+		}
+		if templates, err = templates.ParseFiles(overrides...); err != nil {
+			return nil, err
+		}
+	}
+	return &Renderer{templates: templates}, nil
+}
+
+// Guide renders the whole guide as one Markdown document
+func (r *Renderer) Guide(guide Guide) ([]byte, error) {
+	return r.execute(TemplateGuide, guide)
+}
+
+// Pages renders an index page and one page per feature, keyed by file name
+func (r *Renderer) Pages(guide Guide) (map[string][]byte, error) {
+	pages := make(map[string][]byte, len(guide.Features)+1)
+	index, err := r.execute(TemplateIndex, guide)
+	if err != nil {
+		return nil, err
+	}
+	pages["index.md"] = index
+	for _, page := range guide.Features {
+		data := FeatureData{Page: page, CommitURL: guide.CommitURL, Heading: "#", Index: "index.md"}
+		content, err := r.execute(TemplateFeature, data)
+		if err != nil {
+			return nil, err
+		}
+		pages[page.Slug+".md"] = content
+	}
+	return pages, nil
+}
+
+// WriteGuide renders the guide to a single file
+func (r *Renderer) WriteGuide(guide Guide, path string) error {
+	content, err := r.Guide(guide)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// WritePages renders the guide as pages in dir, creating it when needed, and returns
+// the number of files written
+func (r *Renderer) WritePages(guide Guide, dir string) (int, error) {
+	pages, err := r.Pages(guide)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+	for name, content := range pages {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			return 0, err
+		}
+	}
+	return len(pages), nil
+}
+
+func (r *Renderer) execute(name string, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatDate formats a time.Time or *time.Time as YYYY-MM-DD, or "unknown"
+func formatDate(value interface{}) string {
+	switch t := value.(type) {
+	case time.Time:
+		return display.Date(t)
+	case *time.Time:
+		return display.OptionalDate(t)
+	}
+	return "unknown"
+}
+
+func commitLink(commitURL, hash string) string {
+	if commitURL == "" {
+		return "`" + display.ShortHash(hash) + "`"
+	}
+	return fmt.Sprintf("[%s](%s)", display.ShortHash(hash), fmt.Sprintf(commitURL, hash))
+}
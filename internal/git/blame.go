@@ -0,0 +1,115 @@
+package git
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+)
+
+// LineOrigin is the commit that last changed a line before it was modified or deleted
+type LineOrigin struct {
+	Path        string
+	Line        int // 1-based line number in the parent version of the file
+	Text        string
+	CommitHash  string
+	AuthorEmail string
+	Date        time.Time
+}
+
+// ChangedLineOrigins blames, on the first parent, the lines that the commit deleted or
+// modified and returns the commits that introduced them. When files is not empty only
+// those paths are considered. Blank lines are ignored
+func (r *Repository) ChangedLineOrigins(hash string, files []string) ([]LineOrigin, error) {
+	commit, err := r.repo.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		return nil, err
+	}
+	if commit.NumParents() == 0 {
+		return nil, nil
+	}
+
+	parent, err := commit.Parent(0)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := parent.Patch(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(files))
+	for _, file := range files {
+		wanted[file] = true
+	}
+
+	var origins []LineOrigin
+	for _, filePatch := range patch.FilePatches() {
+		from, _ := filePatch.Files()
+		if from == nil || filePatch.IsBinary() {
+			continue // Added or binary files have no lines to blame
+		}
+		if len(wanted) > 0 && !wanted[from.Path()] {
+			continue
+		}
+
+		lines := changedLines(filePatch.Chunks())
+		if len(lines) == 0 {
+			continue
+		}
+
+		blame, err := git.Blame(parent, from.Path())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, line := range lines {
+			if line > len(blame.Lines) {
+				continue
+			}
+			blamed := blame.Lines[line-1]
+			origins = append(origins, LineOrigin{
+				Path:        from.Path(),
+				Line:        line,
+				Text:        blamed.Text,
+				CommitHash:  blamed.Hash.String(),
+				AuthorEmail: blamed.Author,
+				Date:        blamed.Date,
+			})
+		}
+	}
+
+	return origins, nil
+}
+
+// changedLines returns the 1-based line numbers, in the old version of a file, of the
+// non-blank lines deleted or replaced by the chunks
+func changedLines(chunks []diff.Chunk) []int {
+	var lines []int
+	line := 0
+	for _, chunk := range chunks {
+		content := splitLines(chunk.Content())
+		switch chunk.Type() {
+		case diff.Equal:
+			line += len(content)
+		case diff.Delete:
+			for _, text := range content {
+				line++
+				if strings.TrimSpace(text) != "" {
+					lines = append(lines, line)
+				}
+			}
+		}
+	}
+	return lines
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
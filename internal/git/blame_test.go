@@ -0,0 +1,51 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangedLineOrigins(t *testing.T) {
+	tr := newTestRepo(t)
+	now := time.Now().Truncate(time.Second)
+
+	first := tr.commit("feat: add calculator", "john@example.com", now.Add(-72*time.Hour), map[string]string{
+		"calc.go":   "package calc\n\nfunc Add(a, b int) int {\n\treturn a - b\n}\n",
+		"README.md": "calc\n",
+	})
+	second := tr.commit("feat: add multiply", "jane@example.com", now.Add(-48*time.Hour), map[string]string{
+		"calc.go": "package calc\n\nfunc Add(a, b int) int {\n\treturn a - b\n}\n\nfunc Mul(a, b int) int {\n\treturn a + b\n}\n",
+	})
+	fix := tr.commit("fix: arithmetic", "bob@example.com", now, map[string]string{
+		"calc.go":   "package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n\nfunc Mul(a, b int) int {\n\treturn a * b\n}\n",
+		"README.md": "calc\nmore docs\n",
+	})
+
+	repo := tr.Repository()
+
+	origins, err := repo.ChangedLineOrigins(fix.String(), []string{"calc.go"})
+	assert.NoError(t, err)
+	if assert.Len(t, origins, 2) {
+		assert.Equal(t, 4, origins[0].Line)
+		assert.Equal(t, first.String(), origins[0].CommitHash)
+		assert.Equal(t, "john@example.com", origins[0].AuthorEmail)
+		assert.Equal(t, "\treturn a - b", origins[0].Text)
+
+		assert.Equal(t, 8, origins[1].Line)
+		assert.Equal(t, second.String(), origins[1].CommitHash)
+		assert.Equal(t, "jane@example.com", origins[1].AuthorEmail)
+		assert.True(t, origins[1].Date.Equal(now.Add(-48*time.Hour)))
+	}
+
+	// Pure additions have nothing to blame
+	origins, err = repo.ChangedLineOrigins(fix.String(), []string{"README.md"})
+	assert.NoError(t, err)
+	assert.Empty(t, origins)
+
+	// The root commit has no parent
+	origins, err = repo.ChangedLineOrigins(first.String(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, origins)
+}
@@ -0,0 +1,58 @@
+package git
+
+import (
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ListFiles returns the paths of all files in the HEAD tree
+func (r *Repository) ListFiles() ([]string, error) {
+	tree, err := r.headTree()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err = tree.Files().ForEach(func(f *object.File) error {
+		files = append(files, f.Name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// ReadFile returns the contents of a file in the HEAD tree
+func (r *Repository) ReadFile(path string) ([]byte, error) {
+	tree, err := r.headTree()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := tree.File(path)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(contents), nil
+}
+
+func (r *Repository) headTree() (*object.Tree, error) {
+	ref, err := r.repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := r.repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	return commit.Tree()
+}
@@ -0,0 +1,34 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListFilesAndReadFile(t *testing.T) {
+	tr := newTestRepo(t)
+	now := time.Now()
+
+	tr.commit("initial commit", "john@example.com", now.Add(-time.Hour), map[string]string{
+		"go.mod":        "module example.com/app\n",
+		"auth/login.go": "package auth\n",
+	})
+	tr.commit("add api", "jane@example.com", now, map[string]string{
+		"api/routes.go": "package api\n",
+	})
+
+	repo := tr.Repository()
+
+	files, err := repo.ListFiles()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"go.mod", "auth/login.go", "api/routes.go"}, files)
+
+	contents, err := repo.ReadFile("go.mod")
+	assert.NoError(t, err)
+	assert.Equal(t, "module example.com/app\n", string(contents))
+
+	_, err = repo.ReadFile("missing.txt")
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// testRepo wraps an in-memory repository so tests can build history without network access
+type testRepo struct {
+	t    *testing.T
+	repo *git.Repository
+	wt   *git.Worktree
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("failed to init repository: %v", err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+
+	return &testRepo{t: t, repo: repo, wt: wt}
+}
+
+func (tr *testRepo) Repository() *Repository {
+	return &Repository{repo: tr.repo}
+}
+
+// commit writes the given files (path -> contents) and commits them
+func (tr *testRepo) commit(message string, email string, when time.Time, files map[string]string) plumbing.Hash {
+	tr.t.Helper()
+
+	for path, contents := range files {
+		f, err := tr.wt.Filesystem.Create(path)
+		if err != nil {
+			tr.t.Fatalf("failed to create %s: %v", path, err)
+		}
+		if _, err := f.Write([]byte(contents)); err != nil {
+			tr.t.Fatalf("failed to write %s: %v", path, err)
+		}
+		f.Close()
+
+		if _, err := tr.wt.Add(path); err != nil {
+			tr.t.Fatalf("failed to add %s: %v", path, err)
+		}
+	}
+
+	hash, err := tr.wt.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: email, Email: email, When: when},
+	})
+	if err != nil {
+		tr.t.Fatalf("failed to commit: %v", err)
+	}
+
+	return hash
+}
+
+// tag creates a lightweight tag, or an annotated one when a message is given
+func (tr *testRepo) tag(name string, hash plumbing.Hash, message string) {
+	tr.t.Helper()
+
+	var opts *git.CreateTagOptions
+	if message != "" {
+		opts = &git.CreateTagOptions{
+			Message: message,
+			Tagger:  &object.Signature{Name: "release", Email: "release@example.com", When: time.Now()},
+		}
+	}
+	if _, err := tr.repo.CreateTag(name, hash, opts); err != nil {
+		tr.t.Fatalf("failed to tag %s: %v", name, err)
+	}
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ResolveRevision returns the commit hash a tag, branch or (abbreviated) hash points to
+func (r *Repository) ResolveRevision(rev string) (string, error) {
+	hash, err := r.repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return "", fmt.Errorf("resolve %q: %w", rev, err)
+	}
+	return hash.String(), nil
+}
+
+// GetCommitRange returns the commits reachable from to but not from from, like
+// "git log from..to", newest first. An empty from lists all history and an empty to means HEAD
+func (r *Repository) GetCommitRange(from, to string) ([]CommitInfo, error) {
+	if to == "" {
+		to = "HEAD"
+	}
+	toHash, err := r.repo.ResolveRevision(plumbing.Revision(to))
+	if err != nil {
+		return nil, fmt.Errorf("resolve %q: %w", to, err)
+	}
+
+	excluded := make(map[plumbing.Hash]bool)
+	if from != "" {
+		fromHash, err := r.repo.ResolveRevision(plumbing.Revision(from))
+		if err != nil {
+			return nil, fmt.Errorf("resolve %q: %w", from, err)
+		}
+		iter, err := r.repo.Log(&git.LogOptions{From: *fromHash})
+		if err != nil {
+			return nil, err
+		}
+		err = iter.ForEach(func(c *object.Commit) error {
+			excluded[c.Hash] = true
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	iter, err := r.repo.Log(&git.LogOptions{From: *toHash})
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []CommitInfo
+	err = iter.ForEach(func(c *object.Commit) error {
+		if excluded[c.Hash] {
+			return nil
+		}
+		files, err := getChangedFiles(c)
+		if err != nil {
+			return err
+		}
+		commits = append(commits, CommitInfo{Commit: c, Files: files})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return commits, nil
+}
@@ -0,0 +1,46 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCommitRange(t *testing.T) {
+	tr := newTestRepo(t)
+	now := time.Now()
+
+	first := tr.commit("initial commit", "john@example.com", now.Add(-3*time.Hour), map[string]string{"a.go": "a"})
+	tr.tag("v1.0.0", first, "")
+	second := tr.commit("feat!: new api", "jane@example.com", now.Add(-2*time.Hour), map[string]string{"b.go": "b"})
+	tr.tag("v2.0.0", second, "Release 2.0.0")
+	third := tr.commit("fix: typo", "john@example.com", now.Add(-time.Hour), map[string]string{"a.go": "A"})
+
+	repo := tr.Repository()
+
+	hash, err := repo.ResolveRevision("v2.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, second.String(), hash, "annotated tags resolve to their commit")
+
+	commits, err := repo.GetCommitRange("v1.0.0", "")
+	assert.NoError(t, err)
+	if assert.Len(t, commits, 2) {
+		assert.Equal(t, third, commits[0].Commit.Hash)
+		assert.Equal(t, []string{"a.go"}, commits[0].Files)
+		assert.Equal(t, second, commits[1].Commit.Hash)
+	}
+
+	commits, err = repo.GetCommitRange("v1.0.0", "v2.0.0")
+	assert.NoError(t, err)
+	if assert.Len(t, commits, 1) {
+		assert.Equal(t, second, commits[0].Commit.Hash)
+	}
+
+	commits, err = repo.GetCommitRange("", "")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 3)
+
+	_, err = repo.GetCommitRange("v9.9.9", "")
+	assert.Error(t, err)
+}
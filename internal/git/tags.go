@@ -0,0 +1,70 @@
+package git
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Tag is a tag pointing at a commit
+type Tag struct {
+	Name       string
+	CommitHash string
+	Date       time.Time // tagger date for annotated tags, commit date for lightweight ones
+}
+
+// Tags returns the repository's tags that point at commits, oldest first
+func (r *Repository) Tags() ([]Tag, error) {
+	refs, err := r.repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		tag := Tag{Name: ref.Name().Short()}
+
+		annotated, err := r.repo.TagObject(ref.Hash())
+		switch {
+		case err == nil:
+			commit, err := annotated.Commit()
+			if errors.Is(err, object.ErrUnsupportedObject) {
+				return nil // tags of trees or blobs
+			}
+			if err != nil {
+				return err
+			}
+			tag.CommitHash = commit.Hash.String()
+			tag.Date = annotated.Tagger.When
+		case errors.Is(err, plumbing.ErrObjectNotFound):
+			commit, err := r.repo.CommitObject(ref.Hash())
+			if errors.Is(err, plumbing.ErrObjectNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			tag.CommitHash = commit.Hash.String()
+			tag.Date = commit.Committer.When
+		default:
+			return err
+		}
+
+		tags = append(tags, tag)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		if !tags[i].Date.Equal(tags[j].Date) {
+			return tags[i].Date.Before(tags[j].Date)
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	return tags, nil
+}
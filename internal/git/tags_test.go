@@ -0,0 +1,31 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	tr := newTestRepo(t)
+	now := time.Now().Truncate(time.Second)
+
+	first := tr.commit("initial commit", "john@example.com", now.Add(-48*time.Hour), map[string]string{"a.go": "a"})
+	tr.tag("v1.0.0", first, "")
+	second := tr.commit("feat: b", "jane@example.com", now.Add(-24*time.Hour), map[string]string{"b.go": "b"})
+	tr.tag("v1.1.0", second, "")
+	tr.tag("v1.1.0-rc1", first, "Release candidate")
+
+	tags, err := tr.Repository().Tags()
+	assert.NoError(t, err)
+
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	assert.Equal(t, []string{"v1.0.0", "v1.1.0", "v1.1.0-rc1"}, names, "annotated tags are dated by their tagger")
+	assert.Equal(t, first.String(), tags[0].CommitHash)
+	assert.True(t, tags[0].Date.Equal(now.Add(-48*time.Hour)))
+	assert.Equal(t, first.String(), tags[2].CommitHash)
+}
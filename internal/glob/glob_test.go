@@ -0,0 +1,52 @@
+package glob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"*.js", "app.js", true},
+		{"*.js", "src/web/app.js", true},
+		{"*.js", "app.ts", false},
+		{"/build/", "build/out.txt", true},
+		{"/build/", "src/build/out.txt", false},
+		{"docs/", "docs/guide/intro.md", true},
+		{"vendor/", "vendor/github.com/x/y.go", true},
+		{"vendor/", "pkg/vendor/y.go", true},
+		{"vendor/", "vendor", false},
+		{"vendor/", "pkg/vendor", false},
+		{"/build/", "build", false},
+		{"vendor", "vendor/github.com/x/y.go", true},
+		{"vendor", "pkg/vendor/y.go", true},
+		{"apps/*/src", "apps/web/src/index.ts", true},
+		{"apps/*/src", "apps/web/lib/src/index.ts", false},
+		{"**/node_modules/**", "web/node_modules/left/index.js", true},
+		{"**/*.pb.go", "api/v1/service.pb.go", true},
+		{"**/*.pb.go", "service.pb.go", true},
+		{"internal/**", "internal/git/clone.go", true},
+		{"file?.go", "file1.go", true},
+		{"file?.go", "file10.go", false},
+		{"[!a]*.go", "b.go", true},
+		{"[!a]*.go", "a.go", false},
+		{"go.sum", "tools/go.sum", true},
+		{"go.sum", "go.summary", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern+" "+tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.expected, MustCompile(tc.pattern).Match(tc.path))
+		})
+	}
+}
+
+func TestCompileEmpty(t *testing.T) {
+	_, err := Compile("  ")
+	assert.Error(t, err)
+}
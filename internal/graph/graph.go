@@ -0,0 +1,222 @@
+// Package graph builds relationship graphs from a report: who owns which features, which
+// features change together and which files change together
+package graph
+
+import (
+	"fmt"
+	"sort"
+
+	"git-history-onboarding/internal/report"
+)
+
+// Node kinds
+const (
+	KindPerson  = "person"
+	KindFeature = "feature"
+	KindFile    = "file"
+)
+
+// Graph is an undirected graph with weighted edges
+type Graph struct {
+	Name  string
+	Nodes []Node // by kind, then label
+	Edges []Edge // heaviest first
+}
+
+// Node is a person, feature or file
+type Node struct {
+	ID    string // unique, e.g. "feature:Payments"
+	Kind  string
+	Label string
+}
+
+// Edge links two nodes
+type Edge struct {
+	From   string
+	To     string
+	Weight float64 // share of a feature, or number of commits
+	Label  string
+	Weak   bool // drawn dashed, e.g. a backup ownership
+}
+
+// Ownership links people to the features they own, primary owners and backup owners
+// with at least minShare of a feature
+func Ownership(rep report.Report, minShare float64) Graph {
+	b := newBuilder("ownership")
+	for _, feature := range rep.Features {
+		add := func(owner report.Owner, backup bool) {
+			if owner.Share < minShare {
+				return
+			}
+			person := b.node(KindPerson, owner.Email)
+			b.edge(person, b.node(KindFeature, feature.Name), owner.Share, fmt.Sprintf("%.0f%%", owner.Share*100), backup)
+		}
+		for _, owner := range feature.Owners {
+			add(owner, false)
+		}
+		for _, owner := range feature.BackupOwners {
+			add(owner, true)
+		}
+	}
+	return b.graph()
+}
+
+// Coupling links features that share at least minCommits commits. A feature is not
+// linked to its ancestors, which hold every commit of their sub-features
+func Coupling(rep report.Report, minCommits int) Graph {
+	parents := make(map[string]string, len(rep.Features))
+	for _, feature := range rep.Features {
+		parents[feature.Name] = feature.Parent
+	}
+	related := func(a, b string) bool {
+		return isAncestor(parents, a, b) || isAncestor(parents, b, a)
+	}
+
+	featuresOf := make(map[string][]string) // commit hash -> features, by name
+	for _, feature := range rep.Features {
+		for _, hash := range feature.Commits {
+			featuresOf[hash] = append(featuresOf[hash], feature.Name)
+		}
+	}
+	shared := make(map[[2]string]int)
+	for _, names := range featuresOf {
+		for i := range names {
+			for j := i + 1; j < len(names); j++ {
+				if !related(names[i], names[j]) {
+					shared[pair(names[i], names[j])]++
+				}
+			}
+		}
+	}
+
+	b := newBuilder("coupling")
+	for p, count := range shared {
+		if count >= minCommits {
+			b.edge(b.node(KindFeature, p[0]), b.node(KindFeature, p[1]), float64(count), fmt.Sprint(count), false)
+		}
+	}
+	return b.graph()
+}
+
+// CoChange links files changed together by at least minCommits commits. Commits
+// changing more than maxFiles files, such as mass renames or formatting, are ignored;
+// a maxFiles of 0 keeps every commit. Excluded files should already be filtered out of
+// the report's commits
+func CoChange(rep report.Report, minCommits, maxFiles int) Graph {
+	together := make(map[[2]string]int)
+	for _, commit := range rep.Commits {
+		if maxFiles > 0 && len(commit.Files) > maxFiles {
+			continue
+		}
+		files := unique(commit.Files)
+		for i := range files {
+			for j := i + 1; j < len(files); j++ {
+				together[pair(files[i], files[j])]++
+			}
+		}
+	}
+
+	b := newBuilder("cochange")
+	for p, count := range together {
+		if count >= minCommits {
+			b.edge(b.node(KindFile, p[0]), b.node(KindFile, p[1]), float64(count), fmt.Sprint(count), false)
+		}
+	}
+	return b.graph()
+}
+
+// Strongest keeps the maxEdges heaviest edges and the nodes they link; a maxEdges of 0
+// keeps the whole graph
+func (g Graph) Strongest(maxEdges int) Graph {
+	if maxEdges <= 0 || len(g.Edges) <= maxEdges {
+		return g
+	}
+	pruned := Graph{Name: g.Name, Edges: g.Edges[:maxEdges:maxEdges]}
+	linked := make(map[string]bool)
+	for _, edge := range pruned.Edges {
+		linked[edge.From], linked[edge.To] = true, true
+	}
+	for _, node := range g.Nodes {
+		if linked[node.ID] {
+			pruned.Nodes = append(pruned.Nodes, node)
+		}
+	}
+	return pruned
+}
+
+type builder struct {
+	name  string
+	nodes map[string]Node
+	edges []Edge
+}
+
+func newBuilder(name string) *builder {
+	return &builder{name: name, nodes: make(map[string]Node)}
+}
+
+// node adds a node once and returns its ID
+func (b *builder) node(kind, label string) string {
+	id := kind + ":" + label
+	b.nodes[id] = Node{ID: id, Kind: kind, Label: label}
+	return id
+}
+
+func (b *builder) edge(from, to string, weight float64, label string, weak bool) {
+	b.edges = append(b.edges, Edge{From: from, To: to, Weight: weight, Label: label, Weak: weak})
+}
+
+func (b *builder) graph() Graph {
+	g := Graph{Name: b.name, Edges: b.edges}
+	for _, node := range b.nodes {
+		g.Nodes = append(g.Nodes, node)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		if g.Nodes[i].Kind != g.Nodes[j].Kind {
+			return g.Nodes[i].Kind < g.Nodes[j].Kind
+		}
+		return g.Nodes[i].Label < g.Nodes[j].Label
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	return g
+}
+
+// isAncestor reports whether ancestor is a parent, grandparent, ... of name
+func isAncestor(parents map[string]string, ancestor, name string) bool {
+	seen := make(map[string]bool)
+	for parent := parents[name]; parent != "" && !seen[parent]; parent = parents[parent] {
+		if parent == ancestor {
+			return true
+		}
+		seen[parent] = true
+	}
+	return false
+}
+
+// pair orders two names so that each pair has a single key
+func pair(a, b string) [2]string {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+func unique(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var result []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
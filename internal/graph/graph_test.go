@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"testing"
+
+	"git-history-onboarding/internal/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func testReport() report.Report {
+	return report.Report{
+		Features: []report.Feature{
+			{
+				Name:         "Payments",
+				Children:     []string{"Payments > Refunds"},
+				Owners:       []report.Owner{{Email: "john@example.com", Share: 0.6}},
+				BackupOwners: []report.Owner{{Email: "jane@example.com", Share: 0.3}, {Email: "amy@example.com", Share: 0.05}},
+				Commits:      []string{"c4", "c3", "c2", "c1"},
+			},
+			{
+				Name:    "Payments > Refunds",
+				Parent:  "Payments",
+				Owners:  []report.Owner{{Email: "jane@example.com", Share: 1}},
+				Commits: []string{"c4", "c3"},
+			},
+			{
+				Name:    "Search",
+				Owners:  []report.Owner{{Email: `"quoted" <q@example.com>`, Share: 1}},
+				Commits: []string{"c4", "c3", "c1"},
+			},
+		},
+		Commits: []report.Commit{
+			{Hash: "c4", Files: []string{"pay/refund.go", "search/index.go"}},
+			{Hash: "c3", Files: []string{"pay/refund.go", "search/index.go", "pay/refund.go"}},
+			{Hash: "c2", Files: []string{"pay/charge.go"}},
+			{Hash: "c1", Files: []string{"pay/charge.go", "search/index.go", "go.mod", "go.sum"}},
+		},
+	}
+}
+
+func TestOwnership(t *testing.T) {
+	g := Ownership(testReport(), 0.1)
+	assert.Len(t, g.Nodes, 6, "amy's share is below the threshold")
+	if assert.Len(t, g.Edges, 4) {
+		assert.Equal(t, Edge{From: "person:jane@example.com", To: "feature:Payments > Refunds", Weight: 1, Label: "100%"}, g.Edges[1], "ties are sorted by ID")
+		assert.Equal(t, Edge{From: "person:jane@example.com", To: "feature:Payments", Weight: 0.3, Label: "30%", Weak: true}, g.Edges[3])
+	}
+	assert.Equal(t, Node{ID: "feature:Payments", Kind: KindFeature, Label: "Payments"}, g.Nodes[0], "nodes are sorted by kind")
+}
+
+func TestCoupling(t *testing.T) {
+	g := Coupling(testReport(), 2)
+	assert.Equal(t, []Edge{
+		{From: "feature:Payments", To: "feature:Search", Weight: 3, Label: "3"},
+		{From: "feature:Payments > Refunds", To: "feature:Search", Weight: 2, Label: "2"},
+	}, g.Edges, "features are not coupled to their parents")
+
+	assert.Len(t, Coupling(testReport(), 3).Edges, 1)
+}
+
+func TestCoChange(t *testing.T) {
+	g := CoChange(testReport(), 2, 3)
+	assert.Equal(t, []Edge{
+		{From: "file:pay/refund.go", To: "file:search/index.go", Weight: 2, Label: "2"},
+	}, g.Edges, "duplicate files count once and c1 changes too many files")
+	assert.Len(t, g.Nodes, 2)
+
+	assert.Len(t, CoChange(testReport(), 1, 0).Edges, 1+6, "c1 links each pair of its 4 files")
+}
+
+func TestStrongest(t *testing.T) {
+	g := Ownership(testReport(), 0).Strongest(2)
+	assert.Len(t, g.Edges, 2)
+	assert.Len(t, g.Nodes, 4, "nodes without edges are dropped")
+	assert.Equal(t, Ownership(testReport(), 0), Ownership(testReport(), 0).Strongest(0))
+}
+
+func TestDOT(t *testing.T) {
+	dot := Ownership(testReport(), 0.1).DOT()
+	assert.Contains(t, dot, "graph \"ownership\" {\n")
+	assert.Contains(t, dot, `"person:\"quoted\" <q@example.com>" [label="\"quoted\" <q@example.com>", shape=ellipse];`)
+	assert.Contains(t, dot, `"person:jane@example.com" -- "feature:Payments > Refunds" [label="100%", penwidth=4.0];`)
+	assert.Contains(t, dot, `[label="30%", penwidth=1.9, style=dashed];`)
+}
+
+func TestMermaid(t *testing.T) {
+	mermaid := Ownership(testReport(), 0.1).Mermaid()
+	assert.Contains(t, mermaid, "graph LR\n  n1[\"Payments\"]\n")
+	assert.Contains(t, mermaid, `n4(["#quot;quoted#quot; <q@example.com>"])`)
+	assert.Contains(t, mermaid, `n5 ---|"100%"| n2`)
+	assert.Contains(t, mermaid, `n5 -.-|"30%"| n1`)
+}
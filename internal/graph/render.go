@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DOT renders the graph in the Graphviz DOT language; heavier edges are drawn thicker
+func (g Graph) DOT() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "graph %s {\n", quoteDOT(g.Name))
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=9];\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s];\n", quoteDOT(node.ID), quoteDOT(node.Label), dotShape(node.Kind))
+	}
+	peak := g.peak()
+	for _, edge := range g.Edges {
+		attrs := fmt.Sprintf("label=%s, penwidth=%.1f", quoteDOT(edge.Label), 1+3*edge.Weight/peak)
+		if edge.Weak {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  %s -- %s [%s];\n", quoteDOT(edge.From), quoteDOT(edge.To), attrs)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart, to paste into a ```mermaid block
+func (g Graph) Mermaid() string {
+	// Mermaid IDs are plain words, so nodes are numbered
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for i, node := range g.Nodes {
+		id := fmt.Sprintf("n%d", i+1)
+		ids[node.ID] = id
+		open, close := mermaidShape(node.Kind)
+		fmt.Fprintf(&b, "  %s%s%s%s\n", id, open, quoteMermaid(node.Label), close)
+	}
+	for _, edge := range g.Edges {
+		link := "---"
+		if edge.Weak {
+			link = "-.-"
+		}
+		fmt.Fprintf(&b, "  %s %s|%s| %s\n", ids[edge.From], link, quoteMermaid(edge.Label), ids[edge.To])
+	}
+	return b.String()
+}
+
+func (g Graph) peak() float64 {
+	peak := 0.0
+	for _, edge := range g.Edges {
+		if edge.Weight > peak {
+			peak = edge.Weight
+		}
+	}
+	if peak == 0 {
+		return 1
+	}
+	return peak
+}
+
+func dotShape(kind string) string {
+	switch kind {
+	case KindPerson:
+		return "ellipse"
+	case KindFeature:
+		return "box"
+	default:
+		return "note"
+	}
+}
+
+func mermaidShape(kind string) (string, string) {
+	switch kind {
+	case KindPerson:
+		return "([", "])"
+	case KindFeature:
+		return "[", "]"
+	default:
+		return "[/", "/]"
+	}
+}
+
+func quoteDOT(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// quoteMermaid quotes a label, with quotes and pipes as Mermaid entity codes
+func quoteMermaid(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "|", "#124;", "\n", " ").Replace(s) + `"`
+}
@@ -0,0 +1,183 @@
+package issues
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"git-history-onboarding/internal/models"
+)
+
+// ErrNotFound is returned when the tracker has no issue with the referenced ID
+var ErrNotFound = errors.New("issue not found")
+
+// Issue is the tracker data for a referenced issue
+type Issue struct {
+	Title     string
+	Labels    []string // for Jira, the issue type is included as a label
+	CreatedAt time.Time
+}
+
+// Client fetches issues from a tracker
+type Client interface {
+	Fetch(ctx context.Context, id string) (*Issue, error)
+}
+
+// GitHubClient reads issues from the GitHub REST API
+type GitHubClient struct {
+	BaseURL    string // defaults to https://api.github.com
+	Owner      string
+	Repo       string
+	Token      string
+	HTTPClient *http.Client
+}
+
+func (c *GitHubClient) Fetch(ctx context.Context, id string) (*Issue, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/issues/%s",
+		baseURL(c.BaseURL, "https://api.github.com"), url.PathEscape(c.Owner), url.PathEscape(c.Repo), url.PathEscape(id))
+
+	headers := map[string]string{"Accept": "application/vnd.github+json"}
+	if c.Token != "" {
+		headers["Authorization"] = "Bearer " + c.Token
+	}
+
+	var body struct {
+		Title     string    `json:"title"`
+		CreatedAt time.Time `json:"created_at"`
+		Labels    []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	}
+	if err := getJSON(ctx, c.HTTPClient, endpoint, headers, &body); err != nil {
+		return nil, err
+	}
+
+	issue := &Issue{Title: body.Title, CreatedAt: body.CreatedAt}
+	for _, label := range body.Labels {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	return issue, nil
+}
+
+// GitLabClient reads issues from the GitLab REST API
+type GitLabClient struct {
+	BaseURL    string // defaults to https://gitlab.com
+	Project    string // numeric ID or "group/project" path
+	Token      string
+	HTTPClient *http.Client
+}
+
+func (c *GitLabClient) Fetch(ctx context.Context, id string) (*Issue, error) {
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/issues/%s",
+		baseURL(c.BaseURL, "https://gitlab.com"), url.PathEscape(c.Project), url.PathEscape(id))
+
+	headers := map[string]string{}
+	if c.Token != "" {
+		headers["PRIVATE-TOKEN"] = c.Token
+	}
+
+	var body struct {
+		Title     string    `json:"title"`
+		CreatedAt time.Time `json:"created_at"`
+		Labels    []string  `json:"labels"`
+	}
+	if err := getJSON(ctx, c.HTTPClient, endpoint, headers, &body); err != nil {
+		return nil, err
+	}
+
+	return &Issue{Title: body.Title, Labels: body.Labels, CreatedAt: body.CreatedAt}, nil
+}
+
+// JiraClient reads issues from the Jira REST API
+type JiraClient struct {
+	BaseURL    string // e.g., https://acme.atlassian.net
+	Email      string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// jiraTimeLayout is the timestamp format Jira uses, e.g. 2024-01-02T15:04:05.000+0000
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+func (c *JiraClient) Fetch(ctx context.Context, id string) (*Issue, error) {
+	endpoint := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=summary,labels,created,issuetype",
+		strings.TrimSuffix(c.BaseURL, "/"), url.PathEscape(id))
+
+	headers := map[string]string{"Accept": "application/json"}
+	if c.Token != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(c.Email+":"+c.Token))
+	}
+
+	var body struct {
+		Fields struct {
+			Summary   string   `json:"summary"`
+			Labels    []string `json:"labels"`
+			Created   string   `json:"created"`
+			IssueType struct {
+				Name string `json:"name"`
+			} `json:"issuetype"`
+		} `json:"fields"`
+	}
+	if err := getJSON(ctx, c.HTTPClient, endpoint, headers, &body); err != nil {
+		return nil, err
+	}
+
+	issue := &Issue{Title: body.Fields.Summary, Labels: body.Fields.Labels}
+	if body.Fields.IssueType.Name != "" {
+		issue.Labels = append(issue.Labels, body.Fields.IssueType.Name)
+	}
+	if created, err := time.Parse(jiraTimeLayout, body.Fields.Created); err == nil {
+		issue.CreatedAt = created
+	}
+	return issue, nil
+}
+
+func baseURL(configured, fallback string) string {
+	if configured == "" {
+		return fallback
+	}
+	return strings.TrimSuffix(configured, "/")
+}
+
+func getJSON(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, target interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("GET %s: %s", endpoint, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// refWithIssue copies tracker data onto a reference
+func refWithIssue(ref models.IssueRef, issue *Issue) models.IssueRef {
+	ref.Title = issue.Title
+	ref.Labels = issue.Labels
+	ref.CreatedAt = issue.CreatedAt
+	return ref
+}
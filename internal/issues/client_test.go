@@ -0,0 +1,83 @@
+package issues
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/repos/acme/shop/issues/123":
+			w.Write([]byte(`{"title": "Cart crashes", "created_at": "2024-01-02T10:00:00Z", "labels": [{"name": "bug"}, {"name": "cart"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := &GitHubClient{BaseURL: server.URL, Owner: "acme", Repo: "shop", Token: "secret"}
+
+	issue, err := client.Fetch(context.Background(), "123")
+	assert.NoError(t, err)
+	assert.Equal(t, "Cart crashes", issue.Title)
+	assert.Equal(t, []string{"bug", "cart"}, issue.Labels)
+	assert.Equal(t, time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), issue.CreatedAt)
+
+	_, err = client.Fetch(context.Background(), "999")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGitLabClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		assert.Equal(t, "/api/v4/projects/acme%2Fshop/issues/7", r.URL.EscapedPath())
+		w.Write([]byte(`{"title": "Slow search", "created_at": "2024-03-04T05:06:07Z", "labels": ["performance"]}`))
+	}))
+	defer server.Close()
+
+	client := &GitLabClient{BaseURL: server.URL, Project: "acme/shop", Token: "secret"}
+
+	issue, err := client.Fetch(context.Background(), "7")
+	assert.NoError(t, err)
+	assert.Equal(t, "Slow search", issue.Title)
+	assert.Equal(t, []string{"performance"}, issue.Labels)
+	assert.Equal(t, time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC), issue.CreatedAt)
+}
+
+func TestJiraClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, token, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "dev@acme.com", user)
+		assert.Equal(t, "secret", token)
+		assert.Equal(t, "/rest/api/2/issue/PAY-456", r.URL.Path)
+		w.Write([]byte(`{"fields": {"summary": "Refund rounding", "labels": ["payments"], "created": "2024-05-06T07:08:09.000+0000", "issuetype": {"name": "Bug"}}}`))
+	}))
+	defer server.Close()
+
+	client := &JiraClient{BaseURL: server.URL, Email: "dev@acme.com", Token: "secret"}
+
+	issue, err := client.Fetch(context.Background(), "PAY-456")
+	assert.NoError(t, err)
+	assert.Equal(t, "Refund rounding", issue.Title)
+	assert.Equal(t, []string{"payments", "Bug"}, issue.Labels)
+	assert.True(t, issue.CreatedAt.Equal(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)))
+}
+
+func TestClientServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := (&GitHubClient{BaseURL: server.URL, Owner: "acme", Repo: "shop"}).Fetch(context.Background(), "1")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
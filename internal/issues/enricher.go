@@ -0,0 +1,90 @@
+package issues
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+)
+
+// DefaultBugLabels are issue labels (and Jira issue types) that mark an issue as a bug
+var DefaultBugLabels = []string{"bug", "defect", "regression", "type: bug", "kind/bug"}
+
+// Enricher fetches referenced issues from their trackers, so that references carry
+// their title, labels and creation date before commits are classified
+type Enricher struct {
+	Clients map[string]Client // tracker name -> client
+	cache   map[string]*Issue
+}
+
+func NewEnricher(clients map[string]Client) *Enricher {
+	return &Enricher{
+		Clients: clients,
+		cache:   make(map[string]*Issue),
+	}
+}
+
+// Prefetch fetches every issue the commits reference, each once
+func (e *Enricher) Prefetch(ctx context.Context, extractor *Extractor, commits []git.CommitInfo) error {
+	for _, commit := range commits {
+		for _, ref := range extractor.Extract(commit.Commit.Message) {
+			if _, err := e.fetch(ctx, ref); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Resolve fills in the title, labels and creation date of prefetched issues; other
+// references are returned as they are
+func (e *Enricher) Resolve(refs []models.IssueRef) []models.IssueRef {
+	if len(refs) == 0 {
+		return refs
+	}
+	resolved := make([]models.IssueRef, len(refs))
+	for i, ref := range refs {
+		resolved[i] = ref
+		if issue := e.cache[ref.Tracker+":"+ref.ID]; issue != nil {
+			resolved[i] = refWithIssue(ref, issue)
+		}
+	}
+	return resolved
+}
+
+// fetch returns the issue behind a reference, or nil when there is no client for its
+// tracker or the tracker does not know it
+func (e *Enricher) fetch(ctx context.Context, ref models.IssueRef) (*Issue, error) {
+	client, ok := e.Clients[ref.Tracker]
+	if !ok {
+		return nil, nil
+	}
+
+	key := ref.Tracker + ":" + ref.ID
+	if issue, ok := e.cache[key]; ok {
+		return issue, nil
+	}
+
+	issue, err := client.Fetch(ctx, ref.ID)
+	if errors.Is(err, ErrNotFound) {
+		issue, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e.cache[key] = issue
+	return issue, nil
+}
+
+// EarliestCreated returns the creation date of the oldest fetched issue, or the zero time
+func EarliestCreated(refs []models.IssueRef) (earliest time.Time) {
+	for _, ref := range refs {
+		if !ref.CreatedAt.IsZero() && (earliest.IsZero() || ref.CreatedAt.Before(earliest)) {
+			earliest = ref.CreatedAt
+		}
+	}
+	return earliest
+}
@@ -0,0 +1,73 @@
+package issues
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClient serves issues from memory and counts requests
+type fakeClient struct {
+	issues map[string]*Issue
+	calls  int
+}
+
+func (f *fakeClient) Fetch(ctx context.Context, id string) (*Issue, error) {
+	f.calls++
+	issue, ok := f.issues[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return issue, nil
+}
+
+func createTestCommit(hash string, message string, when time.Time) git.CommitInfo {
+	return git.CommitInfo{
+		Commit: &object.Commit{
+			Hash: plumbing.NewHash(hash),
+			Author: object.Signature{
+				Name:  "Test User",
+				Email: "test@example.com",
+				When:  when,
+			},
+			Message: message,
+		},
+		Files: []string{"cart.go"},
+	}
+}
+
+func TestPrefetchAndResolve(t *testing.T) {
+	now := time.Now()
+	reported := now.Add(-72 * time.Hour)
+	client := &fakeClient{issues: map[string]*Issue{
+		"1": {Title: "Cart total wrong", Labels: []string{"Bug"}, CreatedAt: reported},
+		"2": {Title: "Add coupons", Labels: []string{"enhancement"}, CreatedAt: now.Add(-24 * time.Hour)},
+	}}
+
+	commits := []git.CommitInfo{
+		createTestCommit("aaa111", "fix: cart total (#1)", now),
+		createTestCommit("bbb222", "cart: handle empty totals, closes #1", now),
+		createTestCommit("ccc333", "feat: coupons (#2, #404)", now),
+	}
+
+	enricher := NewEnricher(map[string]Client{TrackerGitHub: client})
+	assert.NoError(t, enricher.Prefetch(context.Background(), DefaultExtractor(), commits))
+	assert.Equal(t, 3, client.calls, "issues are fetched once")
+
+	refs := enricher.Resolve([]models.IssueRef{
+		{Tracker: TrackerGitHub, ID: "1"},
+		{Tracker: TrackerGitHub, ID: "404"},
+		{Tracker: TrackerJira, ID: "PAY-1"},
+	})
+	assert.Equal(t, models.IssueRef{Tracker: TrackerGitHub, ID: "1", Title: "Cart total wrong", Labels: []string{"Bug"}, CreatedAt: reported}, refs[0])
+	assert.Equal(t, models.IssueRef{Tracker: TrackerGitHub, ID: "404"}, refs[1], "unknown issues are left as references")
+	assert.Equal(t, models.IssueRef{Tracker: TrackerJira, ID: "PAY-1"}, refs[2], "trackers without a client are skipped")
+	assert.Equal(t, reported, EarliestCreated(refs))
+	assert.Equal(t, 3, client.calls, "resolving does not fetch")
+}
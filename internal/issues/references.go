@@ -0,0 +1,145 @@
+package issues
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"git-history-onboarding/internal/models"
+)
+
+// Tracker names used by the default patterns and clients
+const (
+	TrackerGitHub = "github"
+	TrackerGitLab = "gitlab"
+	TrackerJira   = "jira"
+)
+
+// jiraKeyPattern matches any Jira-style key, e.g. "PROJ-456"
+const jiraKeyPattern = `\b([A-Z][A-Z0-9]+-\d+)\b`
+
+// DefaultPatterns recognize GitHub "GH-12" and "#123" references and Jira "PROJ-456" keys
+var DefaultPatterns = Patterns(TrackerGitHub, nil)
+
+// NotJiraProjects are prefixes of standards and encodings that look like Jira keys, e.g.
+// "UTF-8" or "SHA-256"; keys in these projects are ignored unless listed in JiraProjects
+var NotJiraProjects = []string{
+	"AES", "CP", "CVE", "CWE", "ECMA", "ES", "GPL", "HTTP", "IEC", "IEEE", "ISO", "MD", "MPEG",
+	"PEP", "RFC", "RSA", "SHA", "TLS", "UCS", "UTF", "WCAG",
+}
+
+// Patterns returns the default patterns with "#123" references assigned to hashTracker,
+// GitHub or GitLab. With jiraProjects, only keys of those projects are read as Jira
+// issues; otherwise any "PROJ-456" key is, except for NotJiraProjects
+func Patterns(hashTracker string, jiraProjects []string) []models.IssuePatternConfig {
+	jira := jiraKeyPattern
+	if len(jiraProjects) > 0 {
+		quoted := make([]string, len(jiraProjects))
+		for i, project := range jiraProjects {
+			quoted[i] = regexp.QuoteMeta(project)
+		}
+		jira = `\b((?:` + strings.Join(quoted, "|") + `)-\d+)\b`
+	}
+	return []models.IssuePatternConfig{
+		{Tracker: TrackerGitHub, Pattern: `\bGH-(\d+)\b`},
+		{Tracker: hashTracker, Pattern: `(?:^|[^\w&/])#(\d+)\b`},
+		{Tracker: TrackerJira, Pattern: jira},
+	}
+}
+
+type pattern struct {
+	tracker string
+	regex   *regexp.Regexp
+	skip    map[string]bool // projects whose keys are not issues
+}
+
+// Extractor finds issue references in commit messages
+type Extractor struct {
+	patterns []pattern
+}
+
+// NewExtractor compiles the patterns; each must have exactly one capture group for the issue ID.
+// Patterns are tried in order and text claimed by an earlier pattern is not matched again,
+// so "GH-12" is not also read as a Jira key
+func NewExtractor(patterns []models.IssuePatternConfig) (*Extractor, error) {
+	e := &Extractor{}
+	for _, p := range patterns {
+		regex, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s issue pattern %q: %w", p.Tracker, p.Pattern, err)
+		}
+		if regex.NumSubexp() != 1 {
+			return nil, fmt.Errorf("%s issue pattern %q must have one capture group", p.Tracker, p.Pattern)
+		}
+		compiled := pattern{tracker: p.Tracker, regex: regex}
+		if p.Pattern == jiraKeyPattern {
+			compiled.skip = make(map[string]bool, len(NotJiraProjects))
+			for _, project := range NotJiraProjects {
+				compiled.skip[project] = true
+			}
+		}
+		e.patterns = append(e.patterns, compiled)
+	}
+	return e, nil
+}
+
+// DefaultExtractor returns an extractor using DefaultPatterns
+func DefaultExtractor() *Extractor {
+	e, err := NewExtractor(DefaultPatterns)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Extract returns the distinct issue references in a message, in order of first appearance
+func (e *Extractor) Extract(message string) []models.IssueRef {
+	type found struct {
+		start int
+		ref   models.IssueRef
+	}
+
+	claimed := make([]bool, len(message))
+	var matches []found
+	for _, p := range e.patterns {
+		for _, loc := range p.regex.FindAllStringSubmatchIndex(message, -1) {
+			start, end := loc[2], loc[3]
+			if start < 0 || overlaps(claimed, loc[0], loc[1]) {
+				continue
+			}
+			if project, _, _ := strings.Cut(message[start:end], "-"); p.skip[project] {
+				continue
+			}
+			for i := loc[0]; i < loc[1]; i++ {
+				claimed[i] = true
+			}
+			matches = append(matches, found{start: start, ref: models.IssueRef{Tracker: p.tracker, ID: message[start:end]}})
+		}
+	}
+
+	// Order by position in the message
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+
+	seen := make(map[string]bool)
+	var refs []models.IssueRef
+	for _, m := range matches {
+		key := m.ref.Tracker + ":" + m.ref.ID
+		if !seen[key] {
+			seen[key] = true
+			refs = append(refs, m.ref)
+		}
+	}
+	return refs
+}
+
+func overlaps(claimed []bool, start, end int) bool {
+	for i := start; i < end; i++ {
+		if claimed[i] {
+			return true
+		}
+	}
+	return false
+}
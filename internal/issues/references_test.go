@@ -0,0 +1,89 @@
+package issues
+
+import (
+	"testing"
+
+	"git-history-onboarding/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract(t *testing.T) {
+	extractor := DefaultExtractor()
+
+	testCases := []struct {
+		name     string
+		message  string
+		expected []models.IssueRef
+	}{
+		{
+			name:     "GitHub issue number",
+			message:  "fix(auth): handle expired tokens (#123)",
+			expected: []models.IssueRef{{Tracker: TrackerGitHub, ID: "123"}},
+		},
+		{
+			name:     "Jira key",
+			message:  "PAY-456 round refunds to cents",
+			expected: []models.IssueRef{{Tracker: TrackerJira, ID: "PAY-456"}},
+		},
+		{
+			name:    "Footer and body references in order",
+			message: "fix: crash on empty cart\n\nSee JIRA-9 and #7.\n\nFixes: GH-12\nRefs: #7",
+			expected: []models.IssueRef{
+				{Tracker: TrackerJira, ID: "JIRA-9"},
+				{Tracker: TrackerGitHub, ID: "7"},
+				{Tracker: TrackerGitHub, ID: "12"},
+			},
+		},
+		{
+			name:     "Standards are not Jira keys",
+			message:  "fix: read UTF-8 and UTF-16 input, verify SHA-256 sums, ISO-8601 dates per RFC-3339",
+			expected: nil,
+		},
+		{
+			name:     "Jira key next to a standard",
+			message:  "PAY-12: hash refunds with SHA-1",
+			expected: []models.IssueRef{{Tracker: TrackerJira, ID: "PAY-12"}},
+		},
+		{
+			name:     "No references",
+			message:  "refactor: split handler, see docs/readme#usage and &#39;",
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, extractor.Extract(tc.message))
+		})
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	extractor, err := NewExtractor(Patterns(TrackerGitLab, []string{"PAY", "RFC"}))
+	assert.NoError(t, err)
+	assert.Equal(t, []models.IssueRef{
+		{Tracker: TrackerGitLab, ID: "42"},
+		{Tracker: TrackerJira, ID: "PAY-7"},
+		{Tracker: TrackerJira, ID: "RFC-9"},
+		{Tracker: TrackerGitHub, ID: "3"},
+	}, extractor.Extract("Closes #42, PAY-7 and RFC-9, not CORE-1 or UTF-8; GH-3"),
+		"#N goes to GitLab and only listed Jira projects count")
+}
+
+func TestNewExtractor(t *testing.T) {
+	extractor, err := NewExtractor([]models.IssuePatternConfig{
+		{Tracker: TrackerGitLab, Pattern: `(?:^|\s)#(\d+)`},
+		{Tracker: "linear", Pattern: `\b(ENG-\d+)\b`},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.IssueRef{
+		{Tracker: TrackerGitLab, ID: "42"},
+		{Tracker: "linear", ID: "ENG-7"},
+	}, extractor.Extract("Closes #42, ENG-7"))
+
+	_, err = NewExtractor([]models.IssuePatternConfig{{Tracker: "x", Pattern: `#\d+`}})
+	assert.Error(t, err, "patterns need a capture group")
+
+	_, err = NewExtractor([]models.IssuePatternConfig{{Tracker: "x", Pattern: `(`}})
+	assert.Error(t, err)
+}
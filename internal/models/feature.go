@@ -9,12 +9,45 @@ import (
 type Feature struct {
 	Name         string
 	Path         string
+	Parent       string   // name of the parent feature, "" for top-level features
+	Children     []string // names of direct sub-features, sorted
 	Commits      []git.CommitInfo
 	Owners       map[string]float64  // email -> ownership percentage
 	BackupOwners map[string]float64  // email -> ownership percentage
 	CreatedAt    time.Time
 	LastUpdated  time.Time
 	Bugs         []Bug
+	Assignments  map[string]Assignment // commit hash -> why the commit belongs to this feature
+	IssueRefs    map[string][]IssueRef // commit hash -> issues referenced by the commit message
+	Reverts      []Revert              // reverts of this feature's changes
+	BreakingChanges []BreakingChange   // changes announced as breaking, in history order
+}
+
+// Assignment explains why a commit was assigned to a feature
+type Assignment struct {
+	Detector   string  // detector that produced the match
+	Rule       string  // what matched: scope, description, body, file, ...
+	Pattern    string  // the pattern or rule that matched
+	Text       string  // the commit text or file path it matched
+	Confidence float64 // 0 to 1
+}
+
+// BreakingChange is a commit marked breaking with "!" or a BREAKING CHANGE footer
+type BreakingChange struct {
+	CommitHash  string
+	Description string // the footer text, or the commit description when only "!" is used
+	AuthorEmail string
+	Date        time.Time
+}
+
+// Revert links a revert commit to the commit it undid
+type Revert struct {
+	CommitHash   string
+	RevertedHash string // full hash, or as written in the message when not in the history
+	Description  string // subject of the reverted commit
+	AuthorEmail  string
+	Date         time.Time
+	Cancelled    bool // the revert and the reverted commit were both left out of commits and ownership
 }
 
 // Bug represents a bug fix in the codebase
@@ -25,6 +58,26 @@ type Bug struct {
 	FixedAt       time.Time
 	AffectedFiles []string
 	AuthorEmail   string    // Changed from Author to AuthorEmail
+	IntroducedBy  []BugOrigin // commits whose lines the fix changed, oldest first
+	IntroducedAt  time.Time   // date of the oldest introducing commit
+	Issues        []IssueRef  // issues referenced by the fix
+}
+
+// IssueRef is an issue tracker reference found in a commit message, e.g. "#123" or
+// "JIRA-456". Title, Labels and CreatedAt are filled in when the tracker is queried
+type IssueRef struct {
+	Tracker   string // "github", "gitlab", "jira" or a configured tracker name
+	ID        string // "123" or "JIRA-456"
+	Title     string
+	Labels    []string
+	CreatedAt time.Time
+}
+
+// BugOrigin is a commit that introduced lines later changed by a bug fix
+type BugOrigin struct {
+	CommitHash  string
+	AuthorEmail string
+	Date        time.Time
 }
 
 // FeatureDetectionConfig holds configuration for feature detection
@@ -32,7 +85,44 @@ type FeatureDetectionConfig struct {
 	// Patterns to identify features from commit messages
 	FeaturePrefixes []string // e.g., "feat:", "feature:"
 	BugPrefixes     []string // e.g., "fix:", "bug:"
+	NonBugPatterns  []string // regexes for "fixes" that are not bugs, e.g., "(?i)fix typo"
 	
 	// Directory-based feature detection
 	FeaturePaths    map[string]string // e.g., "auth/" -> "Authentication"
-} 
\ No newline at end of file
+
+	// Conventional commit scope aliases, compared ignoring case and "-"/"_"
+	ScopeAliases map[string]string // e.g., "authn" -> "Authentication"
+
+	// Files left out of analysis, added to the default exclusions; "!pattern" re-includes
+	ExcludePatterns []string // e.g., "generated/", "*.snap"
+
+	// Feature hierarchy, e.g., "Refunds" -> "Payments"; names like "Payments > Refunds"
+	// imply their parents without being listed here
+	FeatureParents map[string]string // child -> parent
+
+	// Issue reference patterns, tried in order; each needs one capture group for the ID.
+	// Defaults to GitHub "#123" / "GH-123" and Jira "PROJ-123" references
+	IssuePatterns []IssuePatternConfig
+
+	// Jira projects whose keys are issue references, e.g., "PAY"; when empty, any
+	// "PROJ-123" key is, except standards such as "UTF-8" or "SHA-256"
+	JiraProjects []string
+
+	// Detector composition
+	Detectors     []DetectorConfig // e.g., "paths" before "patterns"; defaults to "patterns"
+	MergePolicy   string           // "union", "first" or "highest"
+	MinConfidence float64          // drop matches below this confidence
+}
+
+// DetectorConfig enables a feature detector with a precedence (higher runs first)
+type DetectorConfig struct {
+	Name       string // "patterns", "paths", "codeowners", "modules" or "directories"
+	Precedence int
+	Depth      int // directory levels used as nested features by "directories"
+}
+
+// IssuePatternConfig is a regular expression extracting issue IDs for a tracker
+type IssuePatternConfig struct {
+	Tracker string
+	Pattern string // e.g., `#(\d+)`
+}
\ No newline at end of file
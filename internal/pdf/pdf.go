@@ -0,0 +1,452 @@
+// Package pdf renders a printable onboarding report in pure Go, with the core PDF fonts
+// so that no font files or external binaries are needed
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"git-history-onboarding/internal/display"
+	"git-history-onboarding/internal/docs"
+	"git-history-onboarding/internal/site"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Page layout, in millimeters
+const (
+	margin     = 20.0
+	lineHeight = 5.5
+	chartWidth = 170.0
+	chartTall  = 40.0
+)
+
+// contributorLimit caps the contributor table of the overview
+const contributorLimit = 20
+
+// Colors as RGB, matching the HTML report
+var (
+	colorPrimary = [3]int{59, 110, 165}
+	colorBackup  = [3]int{157, 187, 224}
+	colorBugs    = [3]int{208, 84, 63}
+	colorMuted   = [3]int{119, 119, 119}
+	colorRule    = [3]int{200, 200, 200}
+	colorText    = [3]int{34, 34, 34}
+)
+
+// Render renders the site as a PDF: a cover page, a table of contents, a repository
+// overview and a section per feature
+func Render(s site.Site) ([]byte, error) {
+	// The table of contents needs the pages of the sections, known after a first pass;
+	// it has one line per entry, so the second pass lays out the same pages
+	first := newDocument(s, nil)
+	first.render()
+	if err := first.pdf.Error(); err != nil {
+		return nil, err
+	}
+	second := newDocument(s, first.pages)
+	second.render()
+
+	var buf bytes.Buffer
+	if err := second.pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type document struct {
+	pdf   *gofpdf.Fpdf
+	tr    func(string) string
+	site  site.Site
+	toc   map[string]int // section title -> page, from the first pass
+	pages map[string]int // section title -> page
+	links map[string]int // section title -> internal link
+}
+
+func newDocument(s site.Site, toc map[string]int) *document {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	// Stable output for the same history
+	pdf.SetCreationDate(s.Repository.LastCommit)
+	pdf.SetModificationDate(s.Repository.LastCommit)
+	pdf.SetCatalogSort(true)
+	pdf.SetTitle("Onboarding report for "+s.Repository.URL, true)
+	pdf.SetCreator("git-analyzer", true)
+
+	d := &document{
+		pdf:   pdf,
+		tr:    pdf.UnicodeTranslatorFromDescriptor(""),
+		site:  s,
+		toc:   toc,
+		pages: make(map[string]int),
+		links: make(map[string]int),
+	}
+	for _, title := range d.sections() {
+		d.links[title] = pdf.AddLink()
+	}
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		if pdf.PageNo() == 1 {
+			return
+		}
+		pdf.SetY(-margin + 5)
+		d.font("", 8, colorMuted)
+		pdf.CellFormat(d.pageWidth()-30, 5, d.fit(s.Repository.URL, d.pageWidth()-32), "", 0, "L", false, 0, "")
+		pdf.CellFormat(30, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	return d
+}
+
+// sections lists the titles of the table of contents in order
+func (d *document) sections() []string {
+	titles := []string{"Overview"}
+	for _, feature := range d.site.Features {
+		titles = append(titles, feature.Name)
+	}
+	return titles
+}
+
+func (d *document) render() {
+	d.cover()
+	d.contents()
+	d.overview()
+	for _, feature := range d.site.Features {
+		d.feature(feature)
+	}
+}
+
+func (d *document) cover() {
+	repo := d.site.Repository
+	d.pdf.AddPage()
+	d.pdf.SetY(90)
+	d.font("B", 28, colorText)
+	d.pdf.CellFormat(0, 14, "Onboarding Report", "", 1, "L", false, 0, "")
+	d.font("", 14, colorPrimary)
+	d.pdf.MultiCell(0, 8, d.tr(repo.URL), "", "L", false)
+	d.pdf.Ln(6)
+	d.font("", 11, colorText)
+	d.pdf.MultiCell(0, lineHeight+1, d.tr(fmt.Sprintf("%s from %s to %s, up to commit %s.",
+		display.Plural(repo.Commits, "commit"), display.Date(repo.FirstCommit), display.Date(repo.LastCommit), display.ShortHash(repo.Head))), "", "L", false)
+	d.pdf.MultiCell(0, lineHeight+1, fmt.Sprintf("%s and %s.",
+		display.Plural(len(d.site.Features), "feature"), display.Plural(len(d.site.Contributors), "contributor")), "", "L", false)
+}
+
+func (d *document) contents() {
+	d.pdf.AddPage()
+	d.heading("Contents")
+	width := d.pageWidth()
+	for _, title := range d.sections() {
+		indent := 0.0
+		for _, feature := range d.site.Features {
+			if feature.Name == title && feature.Parent != "" {
+				indent = 6
+			}
+		}
+		page := ""
+		if n, ok := d.toc[title]; ok {
+			page = fmt.Sprint(n)
+		}
+		d.font("", 11, colorText)
+		y := d.pdf.GetY()
+		d.pdf.SetX(margin + indent)
+		d.pdf.CellFormat(width-indent-15, lineHeight+1, d.fit(title, width-indent-15), "", 0, "L", false, d.links[title], "")
+		d.pdf.CellFormat(15, lineHeight+1, page, "", 1, "R", false, d.links[title], "")
+		d.rule(y + lineHeight + 1)
+	}
+}
+
+func (d *document) overview() {
+	d.section("Overview")
+	repo := d.site.Repository
+	d.paragraph(fmt.Sprintf("%s between %s and %s.", display.Plural(repo.Commits, "commit"), display.Date(repo.FirstCommit), display.Date(repo.LastCommit)))
+
+	d.subheading("Activity")
+	d.activityChart(d.site.Activity)
+
+	d.subheading("Contributors")
+	var rows [][]string
+	for i, contributor := range d.site.Contributors {
+		if i == contributorLimit {
+			break
+		}
+		rows = append(rows, []string{contributor.Email, fmt.Sprint(contributor.Commits), fmt.Sprint(contributor.BugFixes),
+			display.Date(contributor.FirstCommit), display.Date(contributor.LastCommit)})
+	}
+	d.table([]string{"Contributor", "Commits", "Bug fixes", "First", "Last"}, []float64{70, 22, 22, 28, 28}, rows)
+	if len(d.site.Contributors) > contributorLimit {
+		d.muted(fmt.Sprintf("And %d more.", len(d.site.Contributors)-contributorLimit))
+	}
+}
+
+func (d *document) feature(feature site.Feature) {
+	d.section(feature.Name)
+	var intro []string
+	if feature.Parent != "" {
+		intro = append(intro, "Part of "+feature.Parent+".")
+	}
+	intro = append(intro, fmt.Sprintf("Created on %s and last changed on %s, with %s and %s.",
+		display.OptionalDate(feature.CreatedAt), display.OptionalDate(feature.LastUpdated),
+		display.Plural(len(feature.Commits), "commit"), display.Plural(len(feature.Bugs), "bug fix")))
+	if len(feature.Children) > 0 {
+		intro = append(intro, "Sub-features: "+strings.Join(feature.Children, ", ")+".")
+	}
+	d.muted(strings.Join(intro, " "))
+
+	if len(feature.WhoToAsk) > 0 {
+		d.subheading("Who to Ask")
+		for _, contact := range feature.WhoToAsk {
+			d.bullet(contact.Email + ": " + contact.Reason)
+		}
+	}
+
+	d.subheading("Ownership")
+	d.ownershipTable(feature)
+
+	d.subheading("Activity")
+	d.activityChart(feature.Activity)
+	d.paragraph(fmt.Sprintf("%s in the %s before the latest commit of the repository.", display.Plural(feature.RecentCommits, "commit"), docs.RecentWindowText()))
+	for _, commit := range feature.RecentActivity {
+		d.bullet(fmt.Sprintf("%s %s (%s, %s)", display.Date(commit.Date), commit.Subject, display.ShortHash(commit.Hash), commit.AuthorEmail))
+	}
+
+	if len(feature.KeyFiles) > 0 {
+		d.subheading("Key Files")
+		var rows [][]string
+		for _, file := range feature.KeyFiles {
+			rows = append(rows, []string{file.Path, fmt.Sprint(file.Commits)})
+		}
+		d.table([]string{"File", "Commits"}, []float64{148, 22}, rows)
+	}
+
+	if len(feature.Bugs) > 0 {
+		d.subheading("Bug History")
+		for _, bug := range feature.Bugs {
+			description, _, _ := strings.Cut(bug.Description, "\n")
+			d.bullet(fmt.Sprintf("%s %s (%s, %s)", display.Date(bug.FixedAt), strings.TrimSpace(description), display.ShortHash(bug.CommitHash), bug.AuthorEmail))
+		}
+	}
+
+	if len(feature.BreakingChanges) > 0 {
+		d.subheading("Breaking Changes")
+		for _, change := range feature.BreakingChanges {
+			d.bullet(fmt.Sprintf("%s %s (%s, %s)", display.Date(change.Date), change.Description, display.ShortHash(change.CommitHash), change.AuthorEmail))
+		}
+	}
+
+	if len(feature.NotableCommits) > 0 {
+		d.subheading("Notable Commits")
+		for _, commit := range feature.NotableCommits {
+			d.bullet(fmt.Sprintf("%s %s (%s, %s, %s)", display.Date(commit.Date), commit.Subject, display.ShortHash(commit.Hash), commit.AuthorEmail, display.Plural(commit.Files, "file")))
+		}
+	}
+}
+
+// section starts a page with a heading the table of contents links to
+func (d *document) section(title string) {
+	d.pdf.AddPage()
+	d.pages[title] = d.pdf.PageNo()
+	d.pdf.SetLink(d.links[title], -1, -1)
+	d.pdf.Bookmark(d.tr(title), 0, -1)
+	d.heading(title)
+}
+
+func (d *document) heading(text string) {
+	d.font("B", 18, colorText)
+	d.pdf.MultiCell(0, 9, d.tr(text), "", "L", false)
+	d.pdf.Ln(3)
+}
+
+func (d *document) subheading(text string) {
+	// Keep a subheading with at least a few lines of what follows
+	d.needs(lineHeight * 5)
+	d.pdf.Ln(3)
+	d.font("B", 12, colorText)
+	d.pdf.CellFormat(0, 7, d.tr(text), "", 1, "L", false, 0, "")
+	d.pdf.Ln(1)
+}
+
+func (d *document) paragraph(text string) {
+	d.font("", 10, colorText)
+	d.pdf.MultiCell(0, lineHeight, d.tr(text), "", "L", false)
+	d.pdf.Ln(1)
+}
+
+func (d *document) muted(text string) {
+	d.font("", 10, colorMuted)
+	d.pdf.MultiCell(0, lineHeight, d.tr(text), "", "L", false)
+	d.pdf.Ln(1)
+}
+
+func (d *document) bullet(text string) {
+	d.font("", 10, colorText)
+	d.pdf.CellFormat(5, lineHeight, d.tr("•"), "", 0, "L", false, 0, "")
+	d.pdf.MultiCell(0, lineHeight, d.tr(text), "", "L", false)
+}
+
+// table draws a header row and rows of single-line cells; columns after the first
+// are right-aligned
+func (d *document) table(headers []string, widths []float64, rows [][]string) {
+	row := func(cells []string, style string) {
+		d.needs(lineHeight + 1)
+		d.font(style, 9, colorText)
+		y := d.pdf.GetY()
+		for i, cell := range cells {
+			align := "R"
+			if i == 0 {
+				align = "L"
+			}
+			d.pdf.CellFormat(widths[i], lineHeight+1, d.fit(cell, widths[i]-2), "", 0, align, false, 0, "")
+		}
+		d.pdf.Ln(-1)
+		d.rule(y + lineHeight + 1)
+	}
+	row(headers, "B")
+	for _, cells := range rows {
+		row(cells, "")
+	}
+	d.pdf.Ln(2)
+}
+
+// ownershipTable lists the owners of a feature with a bar for their share
+func (d *document) ownershipTable(feature site.Feature) {
+	if len(feature.Owners) == 0 && len(feature.BackupOwners) == 0 {
+		d.muted("No owners.")
+		return
+	}
+	const emailWidth, roleWidth, shareWidth, barWidth = 70.0, 25.0, 15.0, 60.0
+	d.font("B", 9, colorText)
+	y := d.pdf.GetY()
+	d.pdf.CellFormat(emailWidth, lineHeight+1, "Owner", "", 0, "L", false, 0, "")
+	d.pdf.CellFormat(roleWidth, lineHeight+1, "Role", "", 0, "L", false, 0, "")
+	d.pdf.CellFormat(shareWidth, lineHeight+1, "Share", "", 1, "R", false, 0, "")
+	d.rule(y + lineHeight + 1)
+
+	row := func(email, role string, share float64, color [3]int) {
+		d.needs(lineHeight + 1)
+		d.font("", 9, colorText)
+		y := d.pdf.GetY()
+		d.pdf.CellFormat(emailWidth, lineHeight+1, d.fit(email, emailWidth-2), "", 0, "L", false, 0, "")
+		d.pdf.CellFormat(roleWidth, lineHeight+1, role, "", 0, "L", false, 0, "")
+		d.pdf.CellFormat(shareWidth, lineHeight+1, fmt.Sprintf("%.0f%%", share*100), "", 0, "R", false, 0, "")
+		d.pdf.SetFillColor(color[0], color[1], color[2])
+		d.pdf.Rect(margin+emailWidth+roleWidth+shareWidth+4, y+1.5, share*(barWidth-4), lineHeight-2, "F")
+		d.pdf.Ln(lineHeight + 1)
+		d.rule(y + lineHeight + 1)
+	}
+	for _, owner := range feature.Owners {
+		row(owner.Email, "primary", owner.Share, colorPrimary)
+	}
+	for _, owner := range feature.BackupOwners {
+		row(owner.Email, "backup", owner.Share, colorBackup)
+	}
+	d.pdf.Ln(2)
+}
+
+// activityChart draws a column per month with its commits, the bug fixes among them
+// stacked in another color
+func (d *document) activityChart(months []site.Month) {
+	if len(months) == 0 {
+		d.muted("No commits.")
+		return
+	}
+	peak := 0
+	for _, month := range months {
+		if month.Commits > peak {
+			peak = month.Commits
+		}
+	}
+	if peak == 0 {
+		peak = 1
+	}
+
+	d.needs(chartTall + 8)
+	left, top := margin, d.pdf.GetY()
+	bottom := top + chartTall
+	column := chartWidth / float64(len(months))
+	if column > 8 {
+		column = 8
+	}
+	gap := column / 5
+
+	d.font("", 7, colorMuted)
+	d.pdf.Text(left, top+2, fmt.Sprint(peak))
+	d.pdf.SetDrawColor(colorRule[0], colorRule[1], colorRule[2])
+	d.pdf.Line(left, bottom, left+column*float64(len(months)), bottom)
+	// Label about every 20 millimeters, or only the years when the columns are narrow
+	every := int(20/column) + 1
+	for i, month := range months {
+		x := left + float64(i)*column + gap/2
+		commits := float64(month.Commits) / float64(peak) * (chartTall - 4)
+		bugs := float64(month.BugFixes) / float64(peak) * (chartTall - 4)
+		if commits > bugs {
+			d.pdf.SetFillColor(colorPrimary[0], colorPrimary[1], colorPrimary[2])
+			d.pdf.Rect(x, bottom-commits, column-gap, commits-bugs, "F")
+		}
+		if bugs > 0 {
+			d.pdf.SetFillColor(colorBugs[0], colorBugs[1], colorBugs[2])
+			d.pdf.Rect(x, bottom-bugs, column-gap, bugs, "F")
+		}
+		switch {
+		case every <= 12 && i%every == 0:
+			d.pdf.Text(x, bottom+4, month.Start.Format("2006-01"))
+		case every > 12 && month.Start.Month() == time.January:
+			d.pdf.Text(x, bottom+4, fmt.Sprint(month.Start.Year()))
+		}
+	}
+	d.pdf.SetY(bottom + 6)
+	d.legend()
+}
+
+func (d *document) legend() {
+	d.font("", 8, colorMuted)
+	x, y := margin, d.pdf.GetY()
+	for _, entry := range []struct {
+		label string
+		color [3]int
+	}{{"Commits", colorPrimary}, {"Bug fixes", colorBugs}} {
+		d.pdf.SetFillColor(entry.color[0], entry.color[1], entry.color[2])
+		d.pdf.Rect(x, y+1, 3, 3, "F")
+		d.pdf.Text(x+4, y+3.8, entry.label)
+		x += 6 + d.pdf.GetStringWidth(entry.label)
+	}
+	d.pdf.SetY(y + lineHeight + 1)
+}
+
+// needs starts a new page when less than height is left on the current one
+func (d *document) needs(height float64) {
+	_, pageHeight := d.pdf.GetPageSize()
+	if d.pdf.GetY()+height > pageHeight-margin {
+		d.pdf.AddPage()
+	}
+}
+
+func (d *document) rule(y float64) {
+	d.pdf.SetDrawColor(colorRule[0], colorRule[1], colorRule[2])
+	d.pdf.SetLineWidth(0.2)
+	d.pdf.Line(margin, y, margin+d.pageWidth(), y)
+}
+
+func (d *document) font(style string, size float64, color [3]int) {
+	d.pdf.SetFont("Helvetica", style, size)
+	d.pdf.SetTextColor(color[0], color[1], color[2])
+}
+
+func (d *document) pageWidth() float64 {
+	width, _ := d.pdf.GetPageSize()
+	return width - 2*margin
+}
+
+// fit translates text for the core fonts and shortens it with an ellipsis to fit width
+func (d *document) fit(text string, width float64) string {
+	text = d.tr(text)
+	if d.pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	ellipsis := d.tr("…")
+	for len(text) > 0 && d.pdf.GetStringWidth(text+ellipsis) > width {
+		text = text[:len(text)-1]
+	}
+	return text + ellipsis
+}
@@ -0,0 +1,89 @@
+package pdf
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+	"git-history-onboarding/internal/site"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestCommit(hash string, message string, email string, when time.Time, files []string) git.CommitInfo {
+	return git.CommitInfo{
+		Commit: &object.Commit{
+			Hash:    plumbing.NewHash(hash),
+			Author:  object.Signature{Name: email, Email: email, When: when},
+			Message: message,
+		},
+		Files: files,
+	}
+}
+
+func testSite() site.Site {
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	commits := []git.CommitInfo{
+		createTestCommit("dddd04", "feat(search): facets", "zoë@example.com", start.AddDate(0, 3, 0), []string{"search/facets.go"}),
+		createTestCommit("cccc03", "fix(auth): session expiry", "jane@example.com", start.AddDate(0, 2, 0), []string{"auth/session.go"}),
+		createTestCommit("bbbb02", "feat(auth): sessions", "john@example.com", start.AddDate(0, 0, 1), []string{"auth/session.go"}),
+		createTestCommit("aaaa01", "feat(auth): login", "john@example.com", start, []string{"auth/login.go"}),
+	}
+	featureAnalysis := map[string]*models.Feature{
+		"Authentication": {
+			Name:         "Authentication",
+			Commits:      commits[1:],
+			Owners:       map[string]float64{"john@example.com": 0.67},
+			BackupOwners: map[string]float64{"jane@example.com": 0.33},
+			CreatedAt:    start,
+			LastUpdated:  start.AddDate(0, 2, 0),
+			Bugs: []models.Bug{
+				{CommitHash: commits[1].Commit.Hash.String(), Description: "fix(auth): session expiry", AuthorEmail: "jane@example.com", FixedAt: start.AddDate(0, 2, 0)},
+			},
+		},
+		"Search": {
+			Name:         "Search",
+			Commits:      commits[:1],
+			Owners:       map[string]float64{"zoë@example.com": 1},
+			BackupOwners: map[string]float64{},
+			CreatedAt:    start.AddDate(0, 3, 0),
+			LastUpdated:  start.AddDate(0, 3, 0),
+		},
+	}
+	return site.Build("https://github.com/acme/shop", commits, featureAnalysis, "")
+}
+
+func TestRender(t *testing.T) {
+	s := testSite()
+
+	data, err := Render(s)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+
+	again, err := Render(s)
+	assert.NoError(t, err)
+	assert.Equal(t, data, again, "the same history renders the same bytes")
+}
+
+func TestLayout(t *testing.T) {
+	d := newDocument(testSite(), nil)
+	d.render()
+	assert.NoError(t, d.pdf.Error())
+	assert.Equal(t, map[string]int{"Overview": 3, "Authentication": 4, "Search": 5}, d.pages,
+		"a cover, the contents, then a page per section")
+	assert.Equal(t, 5, d.pdf.PageCount())
+}
+
+func TestFit(t *testing.T) {
+	d := newDocument(site.Site{}, nil)
+	d.font("", 10, colorText)
+
+	assert.Equal(t, "short", d.fit("short", 50))
+	fitted := d.fit("a-very-long-path/that/does/not/fit/in/the/column.go", 30)
+	assert.LessOrEqual(t, d.pdf.GetStringWidth(fitted), 30.0)
+	assert.Equal(t, byte(0x85), fitted[len(fitted)-1], "ends with the cp1252 ellipsis")
+	assert.Equal(t, "zo\xeb", d.fit("zoë", 50), "text is translated for the core fonts")
+}
@@ -0,0 +1,165 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSV table names, in the order they are written
+const (
+	TableFeatures         = "features"
+	TableFeatureOwners    = "feature_owners"
+	TableBugs             = "bugs"
+	TableCommitsByFeature = "commits_by_feature"
+)
+
+// Tables lists every CSV table
+var Tables = []string{TableFeatures, TableFeatureOwners, TableBugs, TableCommitsByFeature}
+
+// CheckTables returns an error naming the first unknown table
+func CheckTables(names []string) error {
+	for _, name := range names {
+		known := false
+		for _, table := range Tables {
+			known = known || name == table
+		}
+		if !known {
+			return fmt.Errorf("unknown table %q, expected one of %s", name, strings.Join(Tables, ", "))
+		}
+	}
+	return nil
+}
+
+// Table returns the rows of a CSV table, header first. Rows follow the report's order
+func (r Report) Table(name string) ([][]string, error) {
+	switch name {
+	case TableFeatures:
+		rows := [][]string{{"feature", "parent", "created_at", "last_updated", "commits", "bugs", "breaking_changes", "reverts", "revert_rate"}}
+		for _, feature := range r.Features {
+			rows = append(rows, []string{
+				feature.Name,
+				feature.Parent,
+				formatOptionalTime(feature.CreatedAt),
+				formatOptionalTime(feature.LastUpdated),
+				strconv.Itoa(len(feature.Commits)),
+				strconv.Itoa(len(feature.Bugs)),
+				strconv.Itoa(len(feature.BreakingChanges)),
+				strconv.Itoa(len(feature.Reverts)),
+				formatShare(feature.RevertRate),
+			})
+		}
+		return rows, nil
+
+	case TableFeatureOwners:
+		rows := [][]string{{"feature", "email", "role", "share"}}
+		for _, feature := range r.Features {
+			for _, owner := range feature.Owners {
+				rows = append(rows, []string{feature.Name, owner.Email, "primary", formatShare(owner.Share)})
+			}
+			for _, owner := range feature.BackupOwners {
+				rows = append(rows, []string{feature.Name, owner.Email, "backup", formatShare(owner.Share)})
+			}
+		}
+		return rows, nil
+
+	case TableBugs:
+		rows := [][]string{{"feature", "commit_hash", "fixed_at", "author_email", "description", "issues", "introduced_at", "introduced_by"}}
+		for _, feature := range r.Features {
+			for _, bug := range feature.Bugs {
+				var refs, origins []string
+				for _, ref := range bug.Issues {
+					refs = append(refs, ref.Tracker+":"+ref.ID)
+				}
+				for _, origin := range bug.IntroducedBy {
+					origins = append(origins, origin.CommitHash)
+				}
+				rows = append(rows, []string{
+					feature.Name,
+					bug.CommitHash,
+					formatTime(bug.FixedAt),
+					bug.AuthorEmail,
+					strings.TrimSpace(bug.Description),
+					strings.Join(refs, " "),
+					formatOptionalTime(bug.IntroducedAt),
+					strings.Join(origins, " "),
+				})
+			}
+		}
+		return rows, nil
+
+	case TableCommitsByFeature:
+		rows := [][]string{{"feature", "commit_hash"}}
+		for _, feature := range r.Features {
+			for _, hash := range feature.Commits {
+				rows = append(rows, []string{feature.Name, hash})
+			}
+		}
+		return rows, nil
+	}
+	return nil, CheckTables([]string{name})
+}
+
+// WriteCSV writes a table as CSV
+func (r Report) WriteCSV(w io.Writer, table string) error {
+	rows, err := r.Table(table)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// WriteCSVDir writes each table to <dir>/<table>.csv, creating dir when needed, and
+// returns the paths written
+func (r Report) WriteCSVDir(dir string, tables []string) ([]string, error) {
+	if err := CheckTables(tables); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, table := range tables {
+		path := filepath.Join(dir, table+".csv")
+		file, err := os.Create(path)
+		if err != nil {
+			return paths, err
+		}
+		err = r.WriteCSV(file, table)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return paths, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatTime(*t)
+}
+
+func formatShare(share float64) string {
+	return strconv.FormatFloat(share, 'f', 4, 64)
+}
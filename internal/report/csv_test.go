@@ -0,0 +1,79 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTable(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	commits, featureAnalysis := testFeatures(start)
+	report := New("repo", commits, featureAnalysis)
+
+	rows, err := report.Table(TableFeatures)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"feature", "parent", "created_at", "last_updated", "commits", "bugs", "breaking_changes", "reverts", "revert_rate"},
+		{"Authentication", "", "2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z", "2", "2", "0", "0", "0.0000"},
+		{"Search", "", "2024-01-03T00:00:00Z", "2024-01-03T00:00:00Z", "1", "0", "0", "0", "0.0000"},
+	}, rows)
+
+	rows, err = report.Table(TableFeatureOwners)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"feature", "email", "role", "share"},
+		{"Authentication", "amy@example.com", "primary", "0.5000"},
+		{"Authentication", "john@example.com", "primary", "0.5000"},
+		{"Authentication", "jane@example.com", "backup", "0.2000"},
+		{"Search", "jane@example.com", "primary", "1.0000"},
+	}, rows)
+
+	rows, err = report.Table(TableBugs)
+	assert.NoError(t, err)
+	if assert.Len(t, rows, 3) {
+		assert.Equal(t, []string{"Authentication", "aaaa01", "2024-01-01T00:00:00Z", "john@example.com", "fix(auth): login", "github:12", "", ""}, rows[1])
+	}
+
+	rows, err = report.Table(TableCommitsByFeature)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 4)
+
+	_, err = report.Table("owners")
+	assert.Error(t, err)
+}
+
+func TestCheckTables(t *testing.T) {
+	assert.NoError(t, CheckTables(Tables))
+	assert.EqualError(t, CheckTables([]string{"bugs", "owners"}),
+		`unknown table "owners", expected one of features, feature_owners, bugs, commits_by_feature`)
+}
+
+func TestWriteCSV(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	commits, featureAnalysis := testFeatures(start)
+	featureAnalysis["Search"].Name = "Search, Indexing"
+	report := New("repo", commits, featureAnalysis)
+
+	var buf bytes.Buffer
+	assert.NoError(t, report.WriteCSV(&buf, TableCommitsByFeature))
+	assert.Contains(t, buf.String(), "feature,commit_hash\n")
+	assert.Contains(t, buf.String(), "\"Search, Indexing\","+commits[0].Commit.Hash.String(), "fields with commas are quoted")
+
+	dir := filepath.Join(t.TempDir(), "tables")
+	paths, err := report.WriteCSVDir(dir, Tables)
+	assert.NoError(t, err)
+	assert.Len(t, paths, len(Tables))
+	data, err := os.ReadFile(filepath.Join(dir, "feature_owners.csv"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "Authentication,jane@example.com,backup,0.2000")
+
+	none := filepath.Join(t.TempDir(), "none")
+	_, err = report.WriteCSVDir(none, []string{"bugs", "owners"})
+	assert.Error(t, err)
+	assert.NoDirExists(t, none, "nothing is written when a table is unknown")
+}
@@ -0,0 +1,197 @@
+package report
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+)
+
+// Owner change kinds
+const (
+	OwnerAdded   = "added"
+	OwnerRemoved = "removed"
+	OwnerShifted = "shifted"
+)
+
+// Diff is what changed between two reports of the same repository
+type Diff struct {
+	From            Snapshot      `json:"from"`
+	To              Snapshot      `json:"to"`
+	FeaturesAdded   []string      `json:"featuresAdded"`   // by name
+	FeaturesRemoved []string      `json:"featuresRemoved"` // by name
+	OwnerChanges    []OwnerChange `json:"ownerChanges"`    // by feature, then largest shift first
+	NewBugs         []FeatureBug  `json:"newBugs"`         // by feature, then oldest fix first
+}
+
+// Snapshot identifies a compared report
+type Snapshot struct {
+	Head       string    `json:"head"`
+	Commits    int       `json:"commits"`
+	LastCommit time.Time `json:"lastCommit"`
+}
+
+// OwnerChange is an owner who joined or left a feature, or whose share moved by at
+// least the threshold. Roles are "primary", "backup" or "" when not an owner
+type OwnerChange struct {
+	Feature  string  `json:"feature"`
+	Email    string  `json:"email"`
+	Kind     string  `json:"kind"`
+	OldShare float64 `json:"oldShare"`
+	NewShare float64 `json:"newShare"`
+	OldRole  string  `json:"oldRole,omitempty"`
+	NewRole  string  `json:"newRole,omitempty"`
+}
+
+// Shift is the change in share, from -1 to 1
+func (c OwnerChange) Shift() float64 {
+	return c.NewShare - c.OldShare
+}
+
+// FeatureBug is a bug fix of a feature
+type FeatureBug struct {
+	Feature string `json:"feature"`
+	Bug     Bug    `json:"bug"`
+}
+
+// Compare reports the features gained and lost between two reports, owners added and
+// removed, shares that moved by at least threshold (0.1 is ten percentage points) and
+// bugs fixed since. A feature counts as present once it has commits. Owners of added or
+// removed features are not listed, and a change of role between primary and backup is
+// always reported
+func Compare(older, newer Report, threshold float64) Diff {
+	diff := Diff{
+		From:            snapshot(older),
+		To:              snapshot(newer),
+		FeaturesAdded:   []string{},
+		FeaturesRemoved: []string{},
+		OwnerChanges:    []OwnerChange{},
+		NewBugs:         []FeatureBug{},
+	}
+
+	before, after := withCommits(older), withCommits(newer)
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			diff.FeaturesRemoved = append(diff.FeaturesRemoved, name)
+		}
+	}
+
+	for _, feature := range after {
+		previous, existed := before[feature.Name]
+		if !existed {
+			diff.FeaturesAdded = append(diff.FeaturesAdded, feature.Name)
+		} else {
+			diff.OwnerChanges = append(diff.OwnerChanges, ownerChanges(previous, feature, threshold)...)
+		}
+
+		fixed := make(map[string]bool, len(previous.Bugs))
+		for _, bug := range previous.Bugs {
+			fixed[bug.CommitHash] = true
+		}
+		for _, bug := range feature.Bugs {
+			if !fixed[bug.CommitHash] {
+				diff.NewBugs = append(diff.NewBugs, FeatureBug{Feature: feature.Name, Bug: bug})
+			}
+		}
+	}
+
+	sort.Strings(diff.FeaturesAdded)
+	sort.Strings(diff.FeaturesRemoved)
+	sort.SliceStable(diff.OwnerChanges, func(i, j int) bool {
+		a, b := diff.OwnerChanges[i], diff.OwnerChanges[j]
+		if a.Feature != b.Feature {
+			return a.Feature < b.Feature
+		}
+		if magnitude(a.Shift()) != magnitude(b.Shift()) {
+			return magnitude(a.Shift()) > magnitude(b.Shift())
+		}
+		return a.Email < b.Email
+	})
+	sort.SliceStable(diff.NewBugs, func(i, j int) bool {
+		return diff.NewBugs[i].Feature < diff.NewBugs[j].Feature
+	})
+	return diff
+}
+
+// Empty reports whether nothing changed
+func (d Diff) Empty() bool {
+	return len(d.FeaturesAdded) == 0 && len(d.FeaturesRemoved) == 0 && len(d.OwnerChanges) == 0 && len(d.NewBugs) == 0
+}
+
+// JSON renders the diff as indented JSON
+func (d Diff) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// withCommits maps names to the features that have commits. Reports list every
+// configured feature, so a feature only counts as present once commits match it
+func withCommits(r Report) map[string]Feature {
+	features := make(map[string]Feature, len(r.Features))
+	for _, feature := range r.Features {
+		if len(feature.Commits) > 0 {
+			features[feature.Name] = feature
+		}
+	}
+	return features
+}
+
+type share struct {
+	value float64
+	role  string
+}
+
+func ownerChanges(older, newer Feature, threshold float64) []OwnerChange {
+	before, after := shares(older), shares(newer)
+	var changes []OwnerChange
+	for email, was := range before {
+		if _, ok := after[email]; !ok {
+			changes = append(changes, OwnerChange{
+				Feature: newer.Name, Email: email, Kind: OwnerRemoved, OldShare: was.value, OldRole: was.role,
+			})
+		}
+	}
+	for email, is := range after {
+		was, ok := before[email]
+		change := OwnerChange{
+			Feature: newer.Name, Email: email, NewShare: is.value, NewRole: is.role,
+			OldShare: was.value, OldRole: was.role,
+		}
+		shift := magnitude(is.value - was.value)
+		switch {
+		case !ok:
+			change.Kind = OwnerAdded
+		case was.role != is.role || shift != 0 && shift >= magnitude(threshold):
+			change.Kind = OwnerShifted
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// shares maps each owner of a feature to their share and role
+func shares(feature Feature) map[string]share {
+	result := make(map[string]share, len(feature.Owners)+len(feature.BackupOwners))
+	for _, owner := range feature.BackupOwners {
+		result[owner.Email] = share{owner.Share, "backup"}
+	}
+	for _, owner := range feature.Owners {
+		result[owner.Email] = share{owner.Share, "primary"}
+	}
+	return result
+}
+
+// magnitude is the size of a shift rounded to a tenth of a percentage point, so that
+// float noise neither orders equal shifts nor misses the threshold
+func magnitude(shift float64) float64 {
+	return math.Round(math.Abs(shift)*1000) / 1000
+}
+
+func snapshot(r Report) Snapshot {
+	return Snapshot{Head: r.Repository.Head, Commits: r.Repository.Commits, LastCommit: r.Repository.LastCommit}
+}
@@ -0,0 +1,140 @@
+package report
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func diffReports() (Report, Report) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	older := Report{
+		SchemaVersion: SchemaVersion,
+		Repository:    Repository{Head: "aaaa", Commits: 10, LastCommit: start},
+		Features: []Feature{
+			{
+				Name:         "Authentication",
+				Commits:      []string{"a1", "b1"},
+				Owners:       []Owner{{Email: "john@example.com", Share: 0.6}, {Email: "amy@example.com", Share: 0.3}},
+				BackupOwners: []Owner{{Email: "jane@example.com", Share: 0.1}},
+				Bugs:         []Bug{{CommitHash: "b1", Description: "fix(auth): login", FixedAt: start}},
+			},
+			{
+				Name:    "Legacy",
+				Commits: []string{"l1"},
+				Owners:  []Owner{{Email: "john@example.com", Share: 1}},
+			},
+			{
+				Name:         "Payments",
+				Commits:      []string{"p1"},
+				Owners:       []Owner{{Email: "amy@example.com", Share: 0.55}, {Email: "bob@example.com", Share: 0.45}},
+				BackupOwners: []Owner{},
+			},
+		},
+	}
+	newer := Report{
+		SchemaVersion: SchemaVersion,
+		Repository:    Repository{Head: "bbbb", Commits: 20, LastCommit: start.AddDate(0, 3, 0)},
+		Features: []Feature{
+			{
+				Name:         "Authentication",
+				Commits:      []string{"a1", "b1", "a2", "b2"},
+				Owners:       []Owner{{Email: "john@example.com", Share: 0.35}, {Email: "jane@example.com", Share: 0.35}},
+				BackupOwners: []Owner{{Email: "zoe@example.com", Share: 0.15}},
+				Bugs: []Bug{
+					{CommitHash: "b1", Description: "fix(auth): login", FixedAt: start},
+					{CommitHash: "b2", Description: "fix(auth): expiry", FixedAt: start.AddDate(0, 2, 0)},
+				},
+			},
+			{
+				Name:         "Payments",
+				Commits:      []string{"p1", "p2"},
+				Owners:       []Owner{{Email: "amy@example.com", Share: 0.5}, {Email: "bob@example.com", Share: 0.5}},
+				BackupOwners: []Owner{},
+			},
+			{
+				Name:    "Search",
+				Commits: []string{"s1", "b3"},
+				Owners:  []Owner{{Email: "zoe@example.com", Share: 1}},
+				Bugs:    []Bug{{CommitHash: "b3", Description: "fix(search): empty query", FixedAt: start.AddDate(0, 1, 0)}},
+			},
+		},
+	}
+	return older, newer
+}
+
+func TestCompare(t *testing.T) {
+	older, newer := diffReports()
+	diff := Compare(older, newer, 0.1)
+
+	assert.Equal(t, Snapshot{Head: "aaaa", Commits: 10, LastCommit: older.Repository.LastCommit}, diff.From)
+	assert.Equal(t, "bbbb", diff.To.Head)
+	assert.Equal(t, []string{"Search"}, diff.FeaturesAdded)
+	assert.Equal(t, []string{"Legacy"}, diff.FeaturesRemoved)
+	assert.Equal(t, []OwnerChange{
+		{Feature: "Authentication", Email: "amy@example.com", Kind: OwnerRemoved, OldShare: 0.3, OldRole: "primary"},
+		{Feature: "Authentication", Email: "jane@example.com", Kind: OwnerShifted, OldShare: 0.1, NewShare: 0.35, OldRole: "backup", NewRole: "primary"},
+		{Feature: "Authentication", Email: "john@example.com", Kind: OwnerShifted, OldShare: 0.6, NewShare: 0.35, OldRole: "primary", NewRole: "primary"},
+		{Feature: "Authentication", Email: "zoe@example.com", Kind: OwnerAdded, NewShare: 0.15, NewRole: "backup"},
+	}, diff.OwnerChanges, "Payments moved by less than the threshold")
+
+	var bugs []string
+	for _, bug := range diff.NewBugs {
+		bugs = append(bugs, bug.Feature+" "+bug.Bug.CommitHash)
+	}
+	assert.Equal(t, []string{"Authentication b2", "Search b3"}, bugs)
+	assert.False(t, diff.Empty())
+}
+
+func TestCompareFeaturesWithoutCommits(t *testing.T) {
+	older, newer := diffReports()
+	// Reports list every configured feature, with or without commits
+	older.Features = append(older.Features, Feature{Name: "Billing", Commits: []string{}, Owners: []Owner{}})
+	newer.Features = append(newer.Features,
+		Feature{Name: "Billing", Commits: []string{"c1"}, Owners: []Owner{{Email: "amy@example.com", Share: 1}}},
+		Feature{Name: "Legacy", Commits: []string{}, Owners: []Owner{}},
+	)
+
+	diff := Compare(older, newer, 0.1)
+	assert.Equal(t, []string{"Billing", "Search"}, diff.FeaturesAdded, "Billing gained its first commits")
+	assert.Equal(t, []string{"Legacy"}, diff.FeaturesRemoved, "Legacy is still configured but has no commits")
+	for _, change := range diff.OwnerChanges {
+		assert.NotEqual(t, "Billing", change.Feature, "owners of added features are not listed")
+	}
+
+	assert.True(t, Compare(newer, newer, 0.1).Empty())
+}
+
+func TestCompareThreshold(t *testing.T) {
+	older, newer := diffReports()
+	var shifted []string
+	for _, change := range Compare(older, newer, 0.05).OwnerChanges {
+		if change.Kind == OwnerShifted {
+			shifted = append(shifted, change.Feature+" "+change.Email)
+		}
+	}
+	assert.Equal(t, []string{
+		"Authentication jane@example.com",
+		"Authentication john@example.com",
+		"Payments amy@example.com",
+		"Payments bob@example.com",
+	}, shifted, "a shift of exactly the threshold counts")
+
+	for _, change := range Compare(older, older, 0).OwnerChanges {
+		t.Errorf("unchanged owner %s of %s reported as %s", change.Email, change.Feature, change.Kind)
+	}
+}
+
+func TestCompareSame(t *testing.T) {
+	older, _ := diffReports()
+	diff := Compare(older, older, 0.1)
+	assert.True(t, diff.Empty())
+
+	data, err := diff.JSON()
+	assert.NoError(t, err)
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, []interface{}{}, decoded["ownerChanges"], "empty lists are [] rather than null")
+}
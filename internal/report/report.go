@@ -0,0 +1,302 @@
+// Package report turns analysis results into a versioned, stably ordered document
+// that dashboards and other tools can consume
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"git-history-onboarding/internal/analysis/features"
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+)
+
+// SchemaVersion is the version of the report format. The minor version changes when
+// fields are added, the major version when fields are renamed, removed or change meaning
+const SchemaVersion = "1.1"
+
+// Report is the full result of analyzing a repository
+type Report struct {
+	SchemaVersion string     `json:"schemaVersion"`
+	Repository    Repository `json:"repository"`
+	Features      []Feature  `json:"features"` // by name
+	Commits       []Commit   `json:"commits"`  // newest first, since 1.1
+}
+
+// Repository describes the analyzed history
+type Repository struct {
+	URL         string    `json:"url"`
+	Head        string    `json:"head"` // hash of the newest analyzed commit
+	Commits     int       `json:"commits"`
+	FirstCommit time.Time `json:"firstCommit"`
+	LastCommit  time.Time `json:"lastCommit"`
+}
+
+// Commit is an analyzed commit
+type Commit struct {
+	Hash        string    `json:"hash"`
+	AuthorEmail string    `json:"authorEmail"`
+	Date        time.Time `json:"date"`
+	Subject     string    `json:"subject"`
+	Files       []string  `json:"files"`
+}
+
+// Feature is a feature with its owners, commits and bug history
+type Feature struct {
+	Name            string           `json:"name"`
+	Parent          string           `json:"parent,omitempty"`
+	Children        []string         `json:"children,omitempty"`
+	CreatedAt       *time.Time       `json:"createdAt,omitempty"` // nil for features without commits
+	LastUpdated     *time.Time       `json:"lastUpdated,omitempty"`
+	Owners          []Owner          `json:"owners"`       // largest share first
+	BackupOwners    []Owner          `json:"backupOwners"` // largest share first
+	Commits         []string         `json:"commits"`      // hashes, newest first
+	Bugs            []Bug            `json:"bugs"`         // oldest fix first
+	BreakingChanges []BreakingChange `json:"breakingChanges,omitempty"`
+	Reverts         []Revert         `json:"reverts,omitempty"`
+	RevertRate      float64          `json:"revertRate"`
+}
+
+// Owner is an author's share of a feature, from 0 to 1
+type Owner struct {
+	Email string  `json:"email"`
+	Share float64 `json:"share"`
+}
+
+// Bug is a bug fix
+type Bug struct {
+	CommitHash    string      `json:"commitHash"`
+	Description   string      `json:"description"`
+	AuthorEmail   string      `json:"authorEmail"`
+	FixedAt       time.Time   `json:"fixedAt"`
+	ReportedAt    *time.Time  `json:"reportedAt,omitempty"`
+	IntroducedAt  *time.Time  `json:"introducedAt,omitempty"`
+	AffectedFiles []string    `json:"affectedFiles"`
+	IntroducedBy  []BugOrigin `json:"introducedBy,omitempty"`
+	Issues        []IssueRef  `json:"issues,omitempty"`
+}
+
+// BugOrigin is a commit that introduced lines a fix changed
+type BugOrigin struct {
+	CommitHash  string    `json:"commitHash"`
+	AuthorEmail string    `json:"authorEmail"`
+	Date        time.Time `json:"date"`
+}
+
+// IssueRef is an issue referenced by a commit
+type IssueRef struct {
+	Tracker   string     `json:"tracker"`
+	ID        string     `json:"id"`
+	Title     string     `json:"title,omitempty"`
+	Labels    []string   `json:"labels,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+// BreakingChange is a commit announced as breaking
+type BreakingChange struct {
+	CommitHash  string    `json:"commitHash"`
+	Description string    `json:"description"`
+	AuthorEmail string    `json:"authorEmail"`
+	Date        time.Time `json:"date"`
+}
+
+// Revert is a commit that undid another one
+type Revert struct {
+	CommitHash   string    `json:"commitHash"`
+	RevertedHash string    `json:"revertedHash"`
+	Description  string    `json:"description"`
+	AuthorEmail  string    `json:"authorEmail"`
+	Date         time.Time `json:"date"`
+	Cancelled    bool      `json:"cancelled,omitempty"`
+}
+
+// New builds a report of the analyzed features of the repository at url, whose
+// history is commits
+func New(url string, commits []git.CommitInfo, featureAnalysis map[string]*models.Feature) Report {
+	report := Report{
+		SchemaVersion: SchemaVersion,
+		Repository:    newRepository(url, commits),
+		Features:      make([]Feature, 0, len(featureAnalysis)),
+	}
+	for _, feature := range featureAnalysis {
+		report.Features = append(report.Features, newFeature(feature))
+	}
+	report.Commits = make([]Commit, 0, len(commits))
+	for _, commit := range sortCommits(commits) {
+		subject, _, _ := strings.Cut(commit.Commit.Message, "\n")
+		files := commit.Files
+		if files == nil {
+			files = []string{}
+		}
+		report.Commits = append(report.Commits, Commit{
+			Hash:        commit.Commit.Hash.String(),
+			AuthorEmail: commit.Commit.Author.Email,
+			Date:        commit.Commit.Author.When,
+			Subject:     strings.TrimSpace(subject),
+			Files:       files,
+		})
+	}
+	sort.Slice(report.Features, func(i, j int) bool {
+		return report.Features[i].Name < report.Features[j].Name
+	})
+	return report
+}
+
+// Read decodes a JSON report, rejecting reports of another major schema version
+func Read(r io.Reader) (Report, error) {
+	var report Report
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return Report{}, fmt.Errorf("failed to decode report: %w", err)
+	}
+	major, _, _ := strings.Cut(report.SchemaVersion, ".")
+	current, _, _ := strings.Cut(SchemaVersion, ".")
+	if major != current {
+		return Report{}, fmt.Errorf("unsupported report schema version %q, expected %s.x", report.SchemaVersion, current)
+	}
+	return report, nil
+}
+
+// Load reads a JSON report from a file
+func Load(path string) (Report, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Report{}, err
+	}
+	defer file.Close()
+	return Read(file)
+}
+
+// JSON renders the report as indented JSON
+func (r Report) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func newRepository(url string, commits []git.CommitInfo) Repository {
+	repository := Repository{URL: url, Commits: len(commits)}
+	for _, commit := range commits {
+		when := commit.Commit.Author.When
+		if repository.Head == "" {
+			repository.Head = commit.Commit.Hash.String()
+		}
+		if repository.FirstCommit.IsZero() || when.Before(repository.FirstCommit) {
+			repository.FirstCommit = when
+		}
+		if when.After(repository.LastCommit) {
+			repository.LastCommit = when
+		}
+	}
+	return repository
+}
+
+func newFeature(feature *models.Feature) Feature {
+	result := Feature{
+		Name:         feature.Name,
+		Parent:       feature.Parent,
+		Children:     feature.Children,
+		CreatedAt:    optionalTime(feature.CreatedAt),
+		LastUpdated:  optionalTime(feature.LastUpdated),
+		Owners:       owners(feature.Owners),
+		BackupOwners: owners(feature.BackupOwners),
+		Commits:      make([]string, 0, len(feature.Commits)),
+		Bugs:         make([]Bug, 0, len(feature.Bugs)),
+		RevertRate:   features.RevertRate(feature),
+	}
+
+	for _, commit := range sortCommits(feature.Commits) {
+		result.Commits = append(result.Commits, commit.Commit.Hash.String())
+	}
+
+	for _, bug := range feature.Bugs {
+		result.Bugs = append(result.Bugs, newBug(bug))
+	}
+	sort.SliceStable(result.Bugs, func(i, j int) bool {
+		if !result.Bugs[i].FixedAt.Equal(result.Bugs[j].FixedAt) {
+			return result.Bugs[i].FixedAt.Before(result.Bugs[j].FixedAt)
+		}
+		return result.Bugs[i].CommitHash < result.Bugs[j].CommitHash
+	})
+
+	for _, change := range features.BreakingTimeline(feature) {
+		result.BreakingChanges = append(result.BreakingChanges, BreakingChange(change))
+	}
+	for _, revert := range feature.Reverts {
+		result.Reverts = append(result.Reverts, Revert(revert))
+	}
+	sort.SliceStable(result.Reverts, func(i, j int) bool {
+		return result.Reverts[i].Date.Before(result.Reverts[j].Date)
+	})
+	return result
+}
+
+func newBug(bug models.Bug) Bug {
+	result := Bug{
+		CommitHash:    bug.CommitHash,
+		Description:   bug.Description,
+		AuthorEmail:   bug.AuthorEmail,
+		FixedAt:       bug.FixedAt,
+		ReportedAt:    optionalTime(bug.ReportedAt),
+		IntroducedAt:  optionalTime(bug.IntroducedAt),
+		AffectedFiles: bug.AffectedFiles,
+	}
+	if result.AffectedFiles == nil {
+		result.AffectedFiles = []string{}
+	}
+	for _, origin := range bug.IntroducedBy {
+		result.IntroducedBy = append(result.IntroducedBy, BugOrigin(origin))
+	}
+	for _, ref := range bug.Issues {
+		result.Issues = append(result.Issues, IssueRef{
+			Tracker:   ref.Tracker,
+			ID:        ref.ID,
+			Title:     ref.Title,
+			Labels:    ref.Labels,
+			CreatedAt: optionalTime(ref.CreatedAt),
+		})
+	}
+	return result
+}
+
+// sortCommits returns the commits newest first, by hash when dates are equal
+func sortCommits(commits []git.CommitInfo) []git.CommitInfo {
+	sorted := make([]git.CommitInfo, len(commits))
+	copy(sorted, commits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Commit, sorted[j].Commit
+		if !a.Author.When.Equal(b.Author.When) {
+			return a.Author.When.After(b.Author.When)
+		}
+		return a.Hash.String() < b.Hash.String()
+	})
+	return sorted
+}
+
+// owners sorts an email -> share map, largest share first
+func owners(shares map[string]float64) []Owner {
+	result := make([]Owner, 0, len(shares))
+	for email, share := range shares {
+		result = append(result, Owner{Email: email, Share: share})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Share != result[j].Share {
+			return result[i].Share > result[j].Share
+		}
+		return result[i].Email < result[j].Email
+	})
+	return result
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,154 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestCommit(hash string, email string, when time.Time) git.CommitInfo {
+	return git.CommitInfo{
+		Commit: &object.Commit{
+			Hash:    plumbing.NewHash(hash),
+			Author:  object.Signature{Name: email, Email: email, When: when},
+			Message: "change " + hash,
+		},
+	}
+}
+
+func testFeatures(start time.Time) ([]git.CommitInfo, map[string]*models.Feature) {
+	day := 24 * time.Hour
+	commits := []git.CommitInfo{
+		createTestCommit("cccc03", "jane@example.com", start.Add(2*day)),
+		createTestCommit("bbbb02", "john@example.com", start.Add(day)),
+		createTestCommit("aaaa01", "john@example.com", start),
+	}
+	return commits, map[string]*models.Feature{
+		"Search": {
+			Name:         "Search",
+			Commits:      commits[:1],
+			Owners:       map[string]float64{"jane@example.com": 1},
+			BackupOwners: map[string]float64{},
+			CreatedAt:    start.Add(2 * day),
+			LastUpdated:  start.Add(2 * day),
+		},
+		"Authentication": {
+			Name:         "Authentication",
+			Commits:      []git.CommitInfo{commits[2], commits[1]},
+			Owners:       map[string]float64{"john@example.com": 0.5, "amy@example.com": 0.5},
+			BackupOwners: map[string]float64{"jane@example.com": 0.2},
+			CreatedAt:    start,
+			LastUpdated:  start.Add(day),
+			Bugs: []models.Bug{
+				{CommitHash: "bbbb02", Description: "fix(auth): expiry", FixedAt: start.Add(day), AuthorEmail: "john@example.com"},
+				{CommitHash: "aaaa01", Description: "fix(auth): login", FixedAt: start, AuthorEmail: "john@example.com",
+					ReportedAt: start.Add(-2 * day),
+					Issues:     []models.IssueRef{{Tracker: "github", ID: "12", CreatedAt: start.Add(-2 * day)}}},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	commits, featureAnalysis := testFeatures(start)
+
+	report := New("https://github.com/acme/shop", commits, featureAnalysis)
+	assert.Equal(t, SchemaVersion, report.SchemaVersion)
+	assert.Equal(t, Repository{
+		URL:         "https://github.com/acme/shop",
+		Head:        commits[0].Commit.Hash.String(),
+		Commits:     3,
+		FirstCommit: start,
+		LastCommit:  start.Add(48 * time.Hour),
+	}, report.Repository)
+
+	if !assert.Len(t, report.Features, 2) {
+		return
+	}
+	auth := report.Features[0]
+	assert.Equal(t, "Authentication", auth.Name, "features are sorted by name")
+	assert.Equal(t, []Owner{{Email: "amy@example.com", Share: 0.5}, {Email: "john@example.com", Share: 0.5}}, auth.Owners, "ties are sorted by email")
+	assert.Equal(t, []string{commits[1].Commit.Hash.String(), commits[2].Commit.Hash.String()}, auth.Commits, "newest commit first")
+	if assert.Len(t, auth.Bugs, 2) {
+		assert.Equal(t, "aaaa01", auth.Bugs[0].CommitHash, "oldest fix first")
+		reported := start.Add(-48 * time.Hour)
+		assert.Equal(t, []IssueRef{{Tracker: "github", ID: "12", CreatedAt: &reported}}, auth.Bugs[0].Issues)
+		if assert.NotNil(t, auth.Bugs[0].ReportedAt) {
+			assert.Equal(t, reported, *auth.Bugs[0].ReportedAt)
+		}
+		assert.Nil(t, auth.Bugs[1].ReportedAt, "fixes without linked issues have no report date")
+		assert.Equal(t, []string{}, auth.Bugs[0].AffectedFiles)
+	}
+	assert.Equal(t, start, *auth.CreatedAt)
+	assert.Empty(t, report.Features[1].BackupOwners)
+
+	if assert.Len(t, report.Commits, 3) {
+		assert.Equal(t, Commit{
+			Hash:        commits[0].Commit.Hash.String(),
+			AuthorEmail: "jane@example.com",
+			Date:        start.Add(48 * time.Hour),
+			Subject:     "change cccc03",
+			Files:       []string{},
+		}, report.Commits[0])
+	}
+
+	empty := New("repo", commits, map[string]*models.Feature{"Admin": {Name: "Admin"}})
+	assert.Nil(t, empty.Features[0].CreatedAt, "features without commits have no dates")
+	assert.Equal(t, []string{}, empty.Features[0].Commits)
+}
+
+func TestJSONIsStable(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	commits, featureAnalysis := testFeatures(start)
+
+	first, err := New("repo", commits, featureAnalysis).JSON()
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		again, err := New("repo", commits, featureAnalysis).JSON()
+		assert.NoError(t, err)
+		assert.Equal(t, string(first), string(again))
+	}
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(first, &decoded))
+	assert.Equal(t, SchemaVersion, decoded["schemaVersion"])
+	auth := decoded["features"].([]interface{})[0].(map[string]interface{})
+	bugs := auth["bugs"].([]interface{})
+	bug := bugs[0].(map[string]interface{})
+	assert.Equal(t, "2023-12-30T00:00:00Z", bug["reportedAt"])
+	assert.Equal(t, "2024-01-01T00:00:00Z", bug["fixedAt"])
+	assert.NotContains(t, bugs[1], "reportedAt", "zero times are left out")
+}
+
+func TestRead(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	commits, featureAnalysis := testFeatures(start)
+	original := New("repo", commits, featureAnalysis)
+	data, err := original.JSON()
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	assert.NoError(t, os.WriteFile(path, data, 0644))
+	loaded, err := Load(path)
+	assert.NoError(t, err)
+	again, err := loaded.JSON()
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(again), "a loaded report encodes the same")
+
+	_, err = Read(strings.NewReader(`{"schemaVersion": "1.0", "features": []}`))
+	assert.NoError(t, err, "older minor versions are readable")
+	_, err = Read(strings.NewReader(`{"schemaVersion": "2.0"}`))
+	assert.EqualError(t, err, `unsupported report schema version "2.0", expected 1.x`)
+	_, err = Read(strings.NewReader(`[`))
+	assert.Error(t, err)
+}
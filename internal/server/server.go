@@ -0,0 +1,479 @@
+// Package server serves an analyzed repository over HTTP: a JSON API and a browser UI
+package server
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"git-history-onboarding/internal/report"
+)
+
+// searchLimit caps each list of search results
+const searchLimit = 50
+
+//go:embed ui.html
+var ui []byte
+
+// Analyze produces a fresh report, e.g. by cloning and analyzing the repository again
+type Analyze func(ctx context.Context) (report.Report, error)
+
+// Server answers API requests from the latest report
+type Server struct {
+	// Addr is the address the server listens on, e.g. "localhost:8080". When set,
+	// re-analysis requests must be sent to it, so that other sites cannot reach the
+	// server through DNS rebinding
+	Addr string
+
+	analyze   Analyze
+	analyzing sync.Mutex // held while a re-analysis runs
+
+	mu         sync.RWMutex
+	report     report.Report
+	analyzedAt time.Time
+	commits    map[string]report.Commit // hash -> commit
+	featuresOf map[string][]string      // commit hash -> feature names
+}
+
+// New analyzes the repository once and serves the result; analyze is run again on
+// POST /api/reanalyze
+func New(ctx context.Context, analyze Analyze) (*Server, error) {
+	rep, err := analyze(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{analyze: analyze}
+	s.load(rep)
+	return s, nil
+}
+
+// NewFromReport serves a saved report; it cannot be re-analyzed
+func NewFromReport(rep report.Report) *Server {
+	s := &Server{}
+	s.load(rep)
+	return s
+}
+
+func (s *Server) load(rep report.Report) {
+	commits := make(map[string]report.Commit, len(rep.Commits))
+	for _, commit := range rep.Commits {
+		commits[commit.Hash] = commit
+	}
+	featuresOf := make(map[string][]string)
+	for _, feature := range rep.Features {
+		for _, hash := range feature.Commits {
+			featuresOf[hash] = append(featuresOf[hash], feature.Name)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report = rep
+	s.analyzedAt = time.Now()
+	s.commits = commits
+	s.featuresOf = featuresOf
+}
+
+// Handler routes the UI and the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleUI)
+	mux.HandleFunc("/api/report", get(s.handleReport))
+	mux.HandleFunc("/api/features", get(s.handleFeatures))
+	mux.HandleFunc("/api/features/", get(s.handleFeature))
+	mux.HandleFunc("/api/commits/", get(s.handleCommit))
+	mux.HandleFunc("/api/owners", get(s.handleOwners))
+	mux.HandleFunc("/api/search", get(s.handleSearch))
+	mux.HandleFunc("/api/reanalyze", s.handleReanalyze)
+	return mux
+}
+
+// FeatureSummary is an entry of GET /api/features
+type FeatureSummary struct {
+	Name        string         `json:"name"`
+	Parent      string         `json:"parent,omitempty"`
+	Owners      []report.Owner `json:"owners"`
+	Commits     int            `json:"commits"`  // within the date range
+	BugFixes    int            `json:"bugFixes"` // within the date range
+	LastUpdated *time.Time     `json:"lastUpdated,omitempty"`
+}
+
+// FeatureDetail is the response of GET /api/features/<name>
+type FeatureDetail struct {
+	report.Feature
+	CommitDetails []report.Commit `json:"commitDetails"` // within the date range, newest first
+}
+
+// CommitDetail is the response of GET /api/commits/<hash>
+type CommitDetail struct {
+	report.Commit
+	Features []string `json:"features"`
+}
+
+// PathOwners is the response of GET /api/owners
+type PathOwners struct {
+	Path     string      `json:"path"`
+	Commits  int         `json:"commits"`
+	Owners   []PathOwner `json:"owners"` // most commits first
+	Features []string    `json:"features"`
+}
+
+// PathOwner counts an author's commits to a path
+type PathOwner struct {
+	Email   string  `json:"email"`
+	Commits int     `json:"commits"`
+	Share   float64 `json:"share"`
+}
+
+// SearchResults is the response of GET /api/search
+type SearchResults struct {
+	People   []Count  `json:"people"`
+	Paths    []Count  `json:"paths"`
+	Features []string `json:"features"`
+}
+
+// Count is a search result with its number of commits
+type Count struct {
+	Name    string `json:"name"`
+	Commits int    `json:"commits"`
+}
+
+func (s *Server) handleUI(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(ui)
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	writeJSON(w, http.StatusOK, s.report)
+}
+
+func (s *Server) handleFeatures(w http.ResponseWriter, r *http.Request) {
+	within, err := parseRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	query := strings.ToLower(r.URL.Query().Get("q"))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	summaries := []FeatureSummary{}
+	for _, feature := range s.report.Features {
+		if query != "" && !strings.Contains(strings.ToLower(feature.Name), query) {
+			continue
+		}
+		summary := FeatureSummary{Name: feature.Name, Parent: feature.Parent, Owners: feature.Owners, LastUpdated: feature.LastUpdated}
+		for _, hash := range feature.Commits {
+			if commit, ok := s.commits[hash]; ok && within.contains(commit.Date) {
+				summary.Commits++
+			}
+		}
+		for _, bug := range feature.Bugs {
+			if within.contains(bug.FixedAt) {
+				summary.BugFixes++
+			}
+		}
+		if summary.Commits == 0 && within.bounded() {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (s *Server) handleFeature(w http.ResponseWriter, r *http.Request) {
+	within, err := parseRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/api/features/")
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, feature := range s.report.Features {
+		if feature.Name != name {
+			continue
+		}
+		detail := FeatureDetail{Feature: feature, CommitDetails: []report.Commit{}}
+		for _, hash := range feature.Commits {
+			if commit, ok := s.commits[hash]; ok && within.contains(commit.Date) {
+				detail.CommitDetails = append(detail.CommitDetails, commit)
+			}
+		}
+		writeJSON(w, http.StatusOK, detail)
+		return
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("feature %q not found", name))
+}
+
+func (s *Server) handleCommit(w http.ResponseWriter, r *http.Request) {
+	prefix := strings.ToLower(strings.TrimPrefix(r.URL.Path, "/api/commits/"))
+	if len(prefix) < 4 {
+		writeError(w, http.StatusBadRequest, errors.New("commit hashes need at least 4 characters"))
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var matches []report.Commit
+	for hash, commit := range s.commits {
+		if strings.HasPrefix(hash, prefix) {
+			matches = append(matches, commit)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		writeError(w, http.StatusNotFound, fmt.Errorf("commit %s not found", prefix))
+	case 1:
+		features := s.featuresOf[matches[0].Hash]
+		if features == nil {
+			features = []string{}
+		}
+		writeJSON(w, http.StatusOK, CommitDetail{Commit: matches[0], Features: features})
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("commit %s is ambiguous", prefix))
+	}
+}
+
+func (s *Server) handleOwners(w http.ResponseWriter, r *http.Request) {
+	within, err := parseRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Query().Get("path"), "/")
+	if path == "" {
+		writeError(w, http.StatusBadRequest, errors.New("missing path"))
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := PathOwners{Path: path, Owners: []PathOwner{}, Features: []string{}}
+	counts := make(map[string]int)
+	features := make(map[string]bool)
+	for _, commit := range s.report.Commits {
+		if !within.contains(commit.Date) || !touches(commit, path) {
+			continue
+		}
+		result.Commits++
+		counts[commit.AuthorEmail]++
+		for _, name := range s.featuresOf[commit.Hash] {
+			features[name] = true
+		}
+	}
+	for email, count := range counts {
+		result.Owners = append(result.Owners, PathOwner{Email: email, Commits: count, Share: float64(count) / float64(result.Commits)})
+	}
+	sort.Slice(result.Owners, func(i, j int) bool {
+		if result.Owners[i].Commits != result.Owners[j].Commits {
+			return result.Owners[i].Commits > result.Owners[j].Commits
+		}
+		return result.Owners[i].Email < result.Owners[j].Email
+	})
+	for name := range features {
+		result.Features = append(result.Features, name)
+	}
+	sort.Strings(result.Features)
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	if query == "" {
+		writeError(w, http.StatusBadRequest, errors.New("missing q"))
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	people := make(map[string]int)
+	paths := make(map[string]int)
+	for _, commit := range s.report.Commits {
+		if strings.Contains(strings.ToLower(commit.AuthorEmail), query) {
+			people[commit.AuthorEmail]++
+		}
+		for _, file := range commit.Files {
+			if strings.Contains(strings.ToLower(file), query) {
+				paths[file]++
+			}
+		}
+	}
+	results := SearchResults{People: topCounts(people), Paths: topCounts(paths), Features: []string{}}
+	for _, feature := range s.report.Features {
+		if strings.Contains(strings.ToLower(feature.Name), query) {
+			results.Features = append(results.Features, feature.Name)
+		}
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (s *Server) handleReanalyze(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, errors.New("use POST"))
+		return
+	}
+	if err := s.checkOrigin(r); err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+	if s.analyze == nil {
+		writeError(w, http.StatusConflict, errors.New("serving a saved report, which cannot be re-analyzed"))
+		return
+	}
+	if !s.analyzing.TryLock() {
+		writeError(w, http.StatusConflict, errors.New("an analysis is already running"))
+		return
+	}
+	defer s.analyzing.Unlock()
+
+	rep, err := s.analyze(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.load(rep)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"analyzedAt": s.analyzedAt,
+		"head":       s.report.Repository.Head,
+		"commits":    s.report.Repository.Commits,
+	})
+}
+
+// checkOrigin rejects requests from other sites: a browser's Origin must be the host
+// the request was sent to, and that host must be the listening address
+func (s *Server) checkOrigin(r *http.Request) error {
+	if s.Addr != "" && !s.listensOn(r.Host) {
+		return fmt.Errorf("requests must be sent to %s, not %s", s.Addr, r.Host)
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host != r.Host {
+			return fmt.Errorf("cross-origin request from %s", origin)
+		}
+	}
+	return nil
+}
+
+// listensOn reports whether host, with its port, reaches the listening address. Besides
+// the address itself, that is localhost or an IP address on the same port: unlike
+// other names, they cannot be rebound to this machine
+func (s *Server) listensOn(host string) bool {
+	if host == s.Addr {
+		return true
+	}
+	_, port, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return false
+	}
+	name, hostPort, err := net.SplitHostPort(host)
+	if err != nil || hostPort != port {
+		return false
+	}
+	return name == "localhost" || net.ParseIP(name) != nil
+}
+
+// touches reports whether a commit changed path or a file below it
+func touches(commit report.Commit, path string) bool {
+	dir := strings.TrimSuffix(path, "/") + "/"
+	for _, file := range commit.Files {
+		if file == path || strings.HasPrefix(file, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// topCounts sorts counts, most commits first, keeping at most searchLimit
+func topCounts(counts map[string]int) []Count {
+	result := make([]Count, 0, len(counts))
+	for name, commits := range counts {
+		result = append(result, Count{Name: name, Commits: commits})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Commits != result[j].Commits {
+			return result[i].Commits > result[j].Commits
+		}
+		return result[i].Name < result[j].Name
+	})
+	if len(result) > searchLimit {
+		result = result[:searchLimit]
+	}
+	return result
+}
+
+// dateRange is the span of the from and to query parameters; a zero bound is open
+type dateRange struct {
+	from, to time.Time // to is exclusive
+}
+
+func (d dateRange) contains(t time.Time) bool {
+	return (d.from.IsZero() || !t.Before(d.from)) && (d.to.IsZero() || t.Before(d.to))
+}
+
+func (d dateRange) bounded() bool {
+	return !d.from.IsZero() || !d.to.IsZero()
+}
+
+// parseRange reads the from and to query parameters, YYYY-MM-DD and both inclusive
+func parseRange(r *http.Request) (dateRange, error) {
+	var d dateRange
+	if value := r.URL.Query().Get("from"); value != "" {
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return d, fmt.Errorf("invalid from date %q, expected YYYY-MM-DD", value)
+		}
+		d.from = t
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return d, fmt.Errorf("invalid to date %q, expected YYYY-MM-DD", value)
+		}
+		d.to = t.AddDate(0, 0, 1)
+	}
+	return d, nil
+}
+
+func get(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, errors.New("use GET"))
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
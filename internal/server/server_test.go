@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func testReport() report.Report {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC) }
+	return report.Report{
+		SchemaVersion: report.SchemaVersion,
+		Repository:    report.Repository{URL: "https://github.com/acme/shop", Head: "cccc03", Commits: 3},
+		Features: []report.Feature{
+			{
+				Name:    "Authentication",
+				Owners:  []report.Owner{{Email: "john@example.com", Share: 0.67}},
+				Commits: []string{"bbbb02", "aaaa01"},
+				Bugs:    []report.Bug{{CommitHash: "bbbb02", Description: "fix(auth): expiry", FixedAt: day(2)}},
+			},
+			{
+				Name:    "Search",
+				Owners:  []report.Owner{{Email: "jane@example.com", Share: 1}},
+				Commits: []string{"cccc03"},
+			},
+		},
+		Commits: []report.Commit{
+			{Hash: "cccc03", AuthorEmail: "jane@example.com", Date: day(20), Subject: "feat(search): facets", Files: []string{"search/facets.go"}},
+			{Hash: "bbbb02", AuthorEmail: "john@example.com", Date: day(2), Subject: "fix(auth): expiry", Files: []string{"auth/session.go", "README.md"}},
+			{Hash: "aaaa01", AuthorEmail: "john@example.com", Date: day(1), Subject: "feat(auth): login", Files: []string{"auth/login.go"}},
+		},
+	}
+}
+
+func request(t *testing.T, handler http.Handler, method, target string, body interface{}) int {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+	if body != nil {
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), body), target)
+	}
+	return recorder.Code
+}
+
+func TestFeatures(t *testing.T) {
+	handler := NewFromReport(testReport()).Handler()
+
+	var all []FeatureSummary
+	assert.Equal(t, http.StatusOK, request(t, handler, "GET", "/api/features", &all))
+	if assert.Len(t, all, 2) {
+		assert.Equal(t, "Authentication", all[0].Name)
+		assert.Equal(t, 2, all[0].Commits)
+		assert.Equal(t, 1, all[0].BugFixes)
+	}
+
+	var ranged []FeatureSummary
+	request(t, handler, "GET", "/api/features?from=2024-01-02&to=2024-01-02", &ranged)
+	if assert.Len(t, ranged, 1, "features without commits in the range are left out") {
+		assert.Equal(t, 1, ranged[0].Commits, "to is inclusive")
+		assert.Equal(t, 1, ranged[0].BugFixes)
+	}
+
+	var filtered []FeatureSummary
+	request(t, handler, "GET", "/api/features?q=sea", &filtered)
+	assert.Len(t, filtered, 1)
+
+	var failure map[string]string
+	assert.Equal(t, http.StatusBadRequest, request(t, handler, "GET", "/api/features?from=yesterday", &failure))
+	assert.Contains(t, failure["error"], "YYYY-MM-DD")
+	assert.Equal(t, http.StatusMethodNotAllowed, request(t, handler, "POST", "/api/features", nil))
+}
+
+func TestFeature(t *testing.T) {
+	handler := NewFromReport(testReport()).Handler()
+
+	var detail FeatureDetail
+	assert.Equal(t, http.StatusOK, request(t, handler, "GET", "/api/features/Authentication?from=2024-01-02", &detail))
+	if assert.Len(t, detail.CommitDetails, 1) {
+		assert.Equal(t, "bbbb02", detail.CommitDetails[0].Hash)
+	}
+	assert.Equal(t, []string{"bbbb02", "aaaa01"}, detail.Commits, "the feature itself is not filtered")
+
+	assert.Equal(t, http.StatusNotFound, request(t, handler, "GET", "/api/features/Billing", nil))
+}
+
+func TestCommit(t *testing.T) {
+	handler := NewFromReport(testReport()).Handler()
+
+	var commit CommitDetail
+	assert.Equal(t, http.StatusOK, request(t, handler, "GET", "/api/commits/BBBB", &commit))
+	assert.Equal(t, "fix(auth): expiry", commit.Subject)
+	assert.Equal(t, []string{"Authentication"}, commit.Features)
+
+	assert.Equal(t, http.StatusBadRequest, request(t, handler, "GET", "/api/commits/bb", nil))
+	assert.Equal(t, http.StatusNotFound, request(t, handler, "GET", "/api/commits/dddd", nil))
+}
+
+func TestOwners(t *testing.T) {
+	handler := NewFromReport(testReport()).Handler()
+
+	var owners PathOwners
+	assert.Equal(t, http.StatusOK, request(t, handler, "GET", "/api/owners?path=auth/", &owners))
+	assert.Equal(t, 2, owners.Commits)
+	assert.Equal(t, []PathOwner{{Email: "john@example.com", Commits: 2, Share: 1}}, owners.Owners)
+	assert.Equal(t, []string{"Authentication"}, owners.Features)
+
+	var file PathOwners
+	request(t, handler, "GET", "/api/owners?path=auth/login.go", &file)
+	assert.Equal(t, 1, file.Commits)
+
+	var prefix PathOwners
+	request(t, handler, "GET", "/api/owners?path=aut", &prefix)
+	assert.Equal(t, 0, prefix.Commits, "paths match whole directory names")
+
+	assert.Equal(t, http.StatusBadRequest, request(t, handler, "GET", "/api/owners", nil))
+}
+
+func TestSearch(t *testing.T) {
+	handler := NewFromReport(testReport()).Handler()
+
+	var results SearchResults
+	assert.Equal(t, http.StatusOK, request(t, handler, "GET", "/api/search?q=JOHN", &results))
+	assert.Equal(t, []Count{{Name: "john@example.com", Commits: 2}}, results.People)
+	assert.Empty(t, results.Paths)
+
+	request(t, handler, "GET", "/api/search?q=search", &results)
+	assert.Equal(t, []Count{{Name: "search/facets.go", Commits: 1}}, results.Paths)
+	assert.Equal(t, []string{"Search"}, results.Features)
+
+	assert.Equal(t, http.StatusBadRequest, request(t, handler, "GET", "/api/search?q=", nil))
+}
+
+func TestReanalyze(t *testing.T) {
+	runs := 0
+	srv, err := New(context.Background(), func(ctx context.Context) (report.Report, error) {
+		runs++
+		rep := testReport()
+		if runs > 1 {
+			rep.Features = rep.Features[:1]
+		}
+		return rep, nil
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	handler := srv.Handler()
+
+	assert.Equal(t, http.StatusMethodNotAllowed, request(t, handler, "GET", "/api/reanalyze", nil))
+	assert.Equal(t, http.StatusOK, request(t, handler, "POST", "/api/reanalyze", nil))
+	assert.Equal(t, 2, runs)
+
+	var features []FeatureSummary
+	request(t, handler, "GET", "/api/features", &features)
+	assert.Len(t, features, 1, "the new report is served")
+
+	saved := NewFromReport(testReport()).Handler()
+	assert.Equal(t, http.StatusConflict, request(t, saved, "POST", "/api/reanalyze", nil))
+
+	t.Run("Origin", func(t *testing.T) {
+		post := func(host, origin string) int {
+			req := httptest.NewRequest("POST", "/api/reanalyze", nil)
+			req.Host = host
+			if origin != "" {
+				req.Header.Set("Origin", origin)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			return recorder.Code
+		}
+		srv.Addr = "localhost:8080"
+		defer func() { srv.Addr = "" }()
+
+		assert.Equal(t, http.StatusOK, post("localhost:8080", "http://localhost:8080"))
+		assert.Equal(t, http.StatusOK, post("127.0.0.1:8080", ""), "not from a browser")
+		assert.Equal(t, http.StatusForbidden, post("localhost:8080", "https://evil.example"))
+		assert.Equal(t, http.StatusForbidden, post("localhost:8080", "null"))
+		assert.Equal(t, http.StatusForbidden, post("evil.example:8080", "http://evil.example:8080"), "DNS rebinding")
+		assert.Equal(t, http.StatusForbidden, post("127.0.0.1:9090", ""))
+	})
+
+	_, err = New(context.Background(), func(ctx context.Context) (report.Report, error) {
+		return report.Report{}, errors.New("clone failed")
+	})
+	assert.Error(t, err)
+}
+
+func TestUI(t *testing.T) {
+	handler := NewFromReport(testReport()).Handler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, strings.HasPrefix(recorder.Body.String(), "<!DOCTYPE html>"))
+
+	assert.Equal(t, http.StatusNotFound, request(t, handler, "GET", "/missing", nil))
+}
@@ -0,0 +1,116 @@
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"git-history-onboarding/internal/report"
+)
+
+// Chart colors
+const (
+	colorPrimary = "#3b6ea5"
+	colorBackup  = "#9dbbe0"
+	colorCommits = "#3b6ea5"
+	colorBugs    = "#d0543f"
+	colorText    = "#333"
+	colorAxis    = "#bbb"
+)
+
+// OwnershipChart draws a horizontal bar per owner, primary owners first
+func OwnershipChart(owners, backups []report.Owner) template.HTML {
+	const (
+		labelWidth = 240
+		barWidth   = 360
+		rowHeight  = 24
+	)
+	type row struct {
+		owner report.Owner
+		color string
+		kind  string
+	}
+	var rows []row
+	for _, owner := range owners {
+		rows = append(rows, row{owner, colorPrimary, "primary"})
+	}
+	for _, owner := range backups {
+		rows = append(rows, row{owner, colorBackup, "backup"})
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	width, height := labelWidth+barWidth+60, len(rows)*rowHeight
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="chart" viewBox="0 0 %d %d" width="%d" height="%d" role="img" aria-label="Ownership">`, width, height, width, height)
+	for i, r := range rows {
+		y := i * rowHeight
+		email := template.HTMLEscapeString(r.owner.Email)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end" fill="%s">%s</text>`, labelWidth-8, y+16, colorText, email)
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%.1f" height="%d" fill="%s"><title>%s: %.0f%% (%s)</title></rect>`,
+			labelWidth, y+4, r.owner.Share*barWidth, rowHeight-8, r.color, email, r.owner.Share*100, r.kind)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" fill="%s">%.0f%%</text>`, float64(labelWidth)+r.owner.Share*barWidth+6, y+16, colorText, r.owner.Share*100)
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// ActivityChart draws a column per month with its commits, the bug fixes among them
+// stacked in another color
+func ActivityChart(months []Month) template.HTML {
+	const (
+		chartHeight = 120
+		labelHeight = 20
+		maxWidth    = 720
+	)
+	if len(months) == 0 {
+		return ""
+	}
+	peak := 0
+	for _, month := range months {
+		if month.Commits > peak {
+			peak = month.Commits
+		}
+	}
+	if peak == 0 {
+		peak = 1
+	}
+
+	column := maxWidth / len(months)
+	if column > 40 {
+		column = 40
+	}
+	if column < 2 {
+		column = 2
+	}
+	gap := column / 5
+	// Label about every 80 pixels, or only the years when the columns are narrow
+	every := (80 + column - 1) / column
+
+	width, height := column*len(months), chartHeight+labelHeight
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="chart" viewBox="0 0 %d %d" width="%d" height="%d" role="img" aria-label="Commits per month">`, width, height, width, height)
+	fmt.Fprintf(&b, `<line x1="0" y1="%d" x2="%d" y2="%d" stroke="%s"/>`, chartHeight, width, chartHeight, colorAxis)
+	for i, month := range months {
+		x := i * column
+		commits := float64(month.Commits) / float64(peak) * chartHeight
+		bugs := float64(month.BugFixes) / float64(peak) * chartHeight
+		label := month.Start.Format("2006-01")
+		fmt.Fprintf(&b, `<g><title>%s: %d commits, %d bug fixes</title>`, label, month.Commits, month.BugFixes)
+		fmt.Fprintf(&b, `<rect x="%d" y="%.1f" width="%d" height="%.1f" fill="%s"/>`, x+gap/2, chartHeight-commits, column-gap, commits-bugs, colorCommits)
+		if month.BugFixes > 0 {
+			fmt.Fprintf(&b, `<rect x="%d" y="%.1f" width="%d" height="%.1f" fill="%s"/>`, x+gap/2, chartHeight-bugs, column-gap, bugs, colorBugs)
+		}
+		b.WriteString(`</g>`)
+
+		switch {
+		case every <= 12 && i%every == 0:
+			fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s" font-size="10">%s</text>`, x, height-4, colorText, label)
+		case every > 12 && month.Start.Month() == 1:
+			fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s" font-size="10">%d</text>`, x, height-4, colorText, month.Start.Year())
+		}
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
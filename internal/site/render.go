@@ -0,0 +1,103 @@
+package site
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+
+	"git-history-onboarding/internal/display"
+	"git-history-onboarding/internal/docs"
+)
+
+//go:embed templates/*.html
+var templates embed.FS
+
+// FeaturePage returns the file name of a feature's page
+func FeaturePage(slug string) string {
+	return "feature-" + slug + ".html"
+}
+
+// Render renders every page of the site, keyed by file name
+func Render(site Site) (map[string][]byte, error) {
+	funcs := template.FuncMap(docs.Funcs())
+	// Feature names to the slugs of their pages, which are numbered when names clash
+	slugs := make(map[string]string, len(site.Features))
+	for _, feature := range site.Features {
+		slugs[feature.Name] = feature.Slug
+	}
+	funcs["slug"] = func(name string) string {
+		if slug, ok := slugs[name]; ok {
+			return slug
+		}
+		return docs.Slug(name)
+	}
+	funcs["page"] = FeaturePage
+	funcs["ownership"] = OwnershipChart
+	funcs["activity"] = ActivityChart
+	anchors := make(map[string]string, len(site.Contributors))
+	for _, contributor := range site.Contributors {
+		anchors[contributor.Email] = contributor.Slug
+	}
+	funcs["contributor"] = func(email string) string {
+		return "contributors.html#" + anchors[email]
+	}
+	funcs["link"] = func(hash string) template.HTML {
+		short := "<code>" + template.HTMLEscapeString(display.ShortHash(hash)) + "</code>"
+		if site.CommitURL == "" {
+			return template.HTML(short)
+		}
+		url := template.HTMLEscapeString(fmt.Sprintf(site.CommitURL, hash))
+		return template.HTML(`<a href="` + url + `">` + short + `</a>`)
+	}
+	parse := func(page string) (*template.Template, error) {
+		return template.New(page).Funcs(funcs).ParseFS(templates, "templates/layout.html", "templates/"+page)
+	}
+
+	pages := make(map[string][]byte)
+	render := func(name, page string, data interface{}) error {
+		tmpl, err := parse(page)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, "layout", data); err != nil {
+			return fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		pages[name] = buf.Bytes()
+		return nil
+	}
+
+	if err := render("index.html", "index.html", site); err != nil {
+		return nil, err
+	}
+	if err := render("contributors.html", "contributors.html", site); err != nil {
+		return nil, err
+	}
+	for _, feature := range site.Features {
+		if err := render(FeaturePage(feature.Slug), "feature.html", feature); err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+// Write renders the site into dir, creating it when needed, and returns the number of
+// pages written
+func Write(site Site, dir string) (int, error) {
+	pages, err := Render(site)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+	for name, content := range pages {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			return 0, err
+		}
+	}
+	return len(pages), nil
+}
@@ -0,0 +1,193 @@
+// Package site generates a self-contained static HTML report: no scripts, stylesheets
+// or fonts are loaded from elsewhere
+package site
+
+import (
+	"sort"
+	"time"
+
+	"git-history-onboarding/internal/docs"
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+	"git-history-onboarding/internal/report"
+)
+
+// Site is the data the pages render
+type Site struct {
+	Repository   report.Repository
+	CommitURL    string        // format for commit links, e.g. "https://github.com/acme/shop/commit/%s"
+	Activity     []Month       // whole repository, oldest first
+	Features     []Feature     // by name
+	Contributors []Contributor // most commits first
+}
+
+// Feature is a feature page
+type Feature struct {
+	docs.Page
+	Activity []Month // oldest first, without gaps
+}
+
+// Month counts the commits and bug fixes of a calendar month
+type Month struct {
+	Start    time.Time
+	Commits  int
+	BugFixes int
+}
+
+// Contributor is an entry of the contributor directory
+type Contributor struct {
+	Email       string
+	Slug        string // anchor in the directory, unique
+	Commits     int
+	BugFixes    int
+	FirstCommit time.Time
+	LastCommit  time.Time
+	Features    []Involvement // most commits first
+}
+
+// Involvement is a contributor's part in a feature
+type Involvement struct {
+	Feature string
+	Slug    string
+	Commits int
+	Share   float64 // ownership share, 0 when not an owner
+	Backup  bool    // the share is a backup ownership
+}
+
+// Build collects the site of the features that have commits
+func Build(url string, commits []git.CommitInfo, featureAnalysis map[string]*models.Feature, commitURL string) Site {
+	guide := docs.Build(url, commits, featureAnalysis, commitURL)
+	site := Site{Repository: guide.Repository, CommitURL: commitURL}
+
+	bugs := make(map[string]string) // bug fix hash -> author
+	for _, page := range guide.Features {
+		source := featureAnalysis[page.Name]
+		featureBugs := make(map[string]bool)
+		for _, bug := range source.Bugs {
+			featureBugs[bug.CommitHash] = true
+			bugs[bug.CommitHash] = bug.AuthorEmail
+		}
+		site.Features = append(site.Features, Feature{
+			Page:     page,
+			Activity: monthlyActivity(source.Commits, featureBugs),
+		})
+	}
+
+	allBugs := make(map[string]bool, len(bugs))
+	for hash := range bugs {
+		allBugs[hash] = true
+	}
+	site.Activity = monthlyActivity(commits, allBugs)
+	site.Contributors = contributors(commits, bugs, site.Features, featureAnalysis)
+	return site
+}
+
+// monthlyActivity buckets commits by month, from the month of the oldest commit to
+// the month of the newest
+func monthlyActivity(commits []git.CommitInfo, bugs map[string]bool) []Month {
+	if len(commits) == 0 {
+		return nil
+	}
+	counts := make(map[time.Time]*Month)
+	var first, last time.Time
+	for _, commit := range commits {
+		when := commit.Commit.Author.When.UTC()
+		start := time.Date(when.Year(), when.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+		month, ok := counts[start]
+		if !ok {
+			month = &Month{Start: start}
+			counts[start] = month
+		}
+		month.Commits++
+		if bugs[commit.Commit.Hash.String()] {
+			month.BugFixes++
+		}
+	}
+
+	var months []Month
+	for start := first; !start.After(last); start = start.AddDate(0, 1, 0) {
+		if month, ok := counts[start]; ok {
+			months = append(months, *month)
+		} else {
+			months = append(months, Month{Start: start})
+		}
+	}
+	return months
+}
+
+func contributors(commits []git.CommitInfo, bugs map[string]string, pages []Feature, featureAnalysis map[string]*models.Feature) []Contributor {
+	byEmail := make(map[string]*Contributor)
+	for _, commit := range commits {
+		email := commit.Commit.Author.Email
+		when := commit.Commit.Author.When
+		contributor, ok := byEmail[email]
+		if !ok {
+			contributor = &Contributor{Email: email, Slug: docs.Slug(email), FirstCommit: when, LastCommit: when}
+			byEmail[email] = contributor
+		}
+		contributor.Commits++
+		if when.Before(contributor.FirstCommit) {
+			contributor.FirstCommit = when
+		}
+		if when.After(contributor.LastCommit) {
+			contributor.LastCommit = when
+		}
+	}
+	for _, email := range bugs {
+		if contributor, ok := byEmail[email]; ok {
+			contributor.BugFixes++
+		}
+	}
+
+	for _, page := range pages {
+		source := featureAnalysis[page.Name]
+		counts := make(map[string]int)
+		for _, commit := range source.Commits {
+			counts[commit.Commit.Author.Email]++
+		}
+		for email, count := range counts {
+			contributor, ok := byEmail[email]
+			if !ok {
+				continue
+			}
+			involvement := Involvement{Feature: page.Name, Slug: page.Slug, Commits: count}
+			if share, ok := source.Owners[email]; ok {
+				involvement.Share = share
+			} else if share, ok := source.BackupOwners[email]; ok {
+				involvement.Share, involvement.Backup = share, true
+			}
+			contributor.Features = append(contributor.Features, involvement)
+		}
+	}
+
+	result := make([]Contributor, 0, len(byEmail))
+	for _, contributor := range byEmail {
+		sort.Slice(contributor.Features, func(i, j int) bool {
+			a, b := contributor.Features[i], contributor.Features[j]
+			if a.Commits != b.Commits {
+				return a.Commits > b.Commits
+			}
+			return a.Feature < b.Feature
+		})
+		result = append(result, *contributor)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Commits != result[j].Commits {
+			return result[i].Commits > result[j].Commits
+		}
+		return result[i].Email < result[j].Email
+	})
+
+	// Emails like "a.b@x" and "a-b@x" share a slug; number the later ones
+	taken := make(map[string]bool)
+	for i := range result {
+		result[i].Slug = docs.UniqueSlug(result[i].Slug, taken)
+	}
+	return result
+}
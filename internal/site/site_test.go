@@ -0,0 +1,167 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/docs"
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+	"git-history-onboarding/internal/report"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestCommit(hash string, message string, email string, when time.Time, files []string) git.CommitInfo {
+	return git.CommitInfo{
+		Commit: &object.Commit{
+			Hash:    plumbing.NewHash(hash),
+			Author:  object.Signature{Name: email, Email: email, When: when},
+			Message: message,
+		},
+		Files: files,
+	}
+}
+
+func testSite() (Site, []git.CommitInfo) {
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	commits := []git.CommitInfo{
+		createTestCommit("dddd04", "feat(search): facets", "<jane>@example.com", start.AddDate(0, 3, 0), []string{"search/facets.go"}),
+		createTestCommit("cccc03", "fix(auth): session expiry", "jane@example.com", start.AddDate(0, 2, 0), []string{"auth/session.go"}),
+		createTestCommit("bbbb02", "feat(auth): sessions", "john@example.com", start.AddDate(0, 0, 1), []string{"auth/session.go"}),
+		createTestCommit("aaaa01", "feat(auth): login", "john@example.com", start, []string{"auth/login.go"}),
+	}
+	featureAnalysis := map[string]*models.Feature{
+		"Authentication": {
+			Name:         "Authentication",
+			Commits:      commits[1:],
+			Owners:       map[string]float64{"john@example.com": 0.67},
+			BackupOwners: map[string]float64{"jane@example.com": 0.33},
+			CreatedAt:    start,
+			LastUpdated:  start.AddDate(0, 2, 0),
+			Bugs: []models.Bug{
+				{CommitHash: commits[1].Commit.Hash.String(), Description: "fix(auth): session expiry", AuthorEmail: "jane@example.com", FixedAt: start.AddDate(0, 2, 0)},
+			},
+		},
+		"Search": {
+			Name:         "Search",
+			Commits:      commits[:1],
+			Owners:       map[string]float64{"<jane>@example.com": 1},
+			BackupOwners: map[string]float64{},
+			CreatedAt:    start.AddDate(0, 3, 0),
+			LastUpdated:  start.AddDate(0, 3, 0),
+		},
+		"Admin": {Name: "Admin"},
+	}
+	return Build("https://github.com/acme/shop", commits, featureAnalysis, "https://github.com/acme/shop/commit/%s"), commits
+}
+
+func TestBuild(t *testing.T) {
+	site, _ := testSite()
+	if !assert.Len(t, site.Features, 2, "features without commits are left out") {
+		return
+	}
+
+	month := func(m time.Month) time.Time { return time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC) }
+	assert.Equal(t, []Month{
+		{Start: month(time.January), Commits: 2},
+		{Start: month(time.February)},
+		{Start: month(time.March), Commits: 1, BugFixes: 1},
+	}, site.Features[0].Activity, "months without commits are kept")
+	assert.Len(t, site.Activity, 4)
+
+	if assert.Len(t, site.Contributors, 3) {
+		john := site.Contributors[0]
+		assert.Equal(t, "john@example.com", john.Email)
+		assert.Equal(t, 2, john.Commits)
+		assert.Equal(t, []Involvement{{Feature: "Authentication", Slug: "authentication", Commits: 2, Share: 0.67}}, john.Features)
+
+		assert.Equal(t, "jane-example-com", site.Contributors[1].Slug)
+		jane := site.Contributors[2]
+		assert.Equal(t, "jane@example.com", jane.Email)
+		assert.Equal(t, "jane-example-com-2", jane.Slug, "the slug of <jane>@example.com is taken")
+		assert.Equal(t, 1, jane.BugFixes)
+		assert.Equal(t, []Involvement{{Feature: "Authentication", Slug: "authentication", Commits: 1, Share: 0.33, Backup: true}}, jane.Features)
+	}
+}
+
+func TestOwnershipChart(t *testing.T) {
+	chart := string(OwnershipChart(
+		[]report.Owner{{Email: "john@example.com", Share: 0.5}},
+		[]report.Owner{{Email: "<jane>", Share: 0.25}},
+	))
+	assert.True(t, strings.HasPrefix(chart, "<svg"))
+	assert.Contains(t, chart, `width="180.0"`)
+	assert.Contains(t, chart, "&lt;jane&gt;: 25% (backup)")
+	assert.NotContains(t, chart, "<jane>")
+	assert.Empty(t, OwnershipChart(nil, nil))
+}
+
+func TestActivityChart(t *testing.T) {
+	months := []Month{
+		{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Commits: 4, BugFixes: 1},
+		{Start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	chart := string(ActivityChart(months))
+	assert.Contains(t, chart, "<title>2024-01: 4 commits, 1 bug fixes</title>")
+	assert.Contains(t, chart, `height="90.0" fill="#3b6ea5"`, "bug fixes are stacked on the commit column")
+	assert.Contains(t, chart, `height="30.0" fill="#d0543f"`)
+	assert.Empty(t, ActivityChart(nil))
+}
+
+func TestWrite(t *testing.T) {
+	site, commits := testSite()
+	dir := filepath.Join(t.TempDir(), "site")
+
+	written, err := Write(site, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, written)
+
+	for _, name := range []string{"index.html", "contributors.html", "feature-authentication.html", "feature-search.html"} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if !assert.NoError(t, err) {
+			continue
+		}
+		html := string(content)
+		assert.NotContains(t, html, "<script", name)
+		assert.NotContains(t, html, "<link", name, "styles are inline")
+		assert.NotContains(t, html, "<jane>", name, "emails are escaped")
+	}
+
+	index, _ := os.ReadFile(filepath.Join(dir, "index.html"))
+	assert.Contains(t, string(index), `<a href="feature-authentication.html">Authentication</a>`)
+	assert.Contains(t, string(index), "<svg")
+
+	feature, _ := os.ReadFile(filepath.Join(dir, "feature-authentication.html"))
+	assert.Contains(t, string(feature), `<a href="https://github.com/acme/shop/commit/`+commits[1].Commit.Hash.String()+`"><code>cccc030</code></a>`)
+	assert.Contains(t, string(feature), `<a href="contributors.html#john-example-com">john@example.com</a>: owns 67% of the feature`)
+	assert.Contains(t, string(feature), `<a href="contributors.html#jane-example-com-2">jane@example.com</a>`)
+
+	contributors, _ := os.ReadFile(filepath.Join(dir, "contributors.html"))
+	assert.Contains(t, string(contributors), `<tr id="jane-example-com">`)
+	assert.Contains(t, string(contributors), `<tr id="jane-example-com-2">`, "anchors are unique")
+	assert.Contains(t, string(contributors), `<a href="feature-authentication.html">Authentication</a> (1, backup owner 33%)`)
+}
+
+func TestRenderClashingNames(t *testing.T) {
+	page := func(name, slug, parent string, children ...string) Feature {
+		return Feature{Page: docs.Page{Feature: report.Feature{Name: name, Parent: parent, Children: children}, Slug: slug}}
+	}
+	site := Site{Features: []Feature{
+		page("Auth > API", "auth-api", ""),
+		page("Auth API", "auth-api-2", "Auth"),
+		page("Auth", "auth", "", "Auth API"),
+	}}
+
+	pages, err := Render(site)
+	assert.NoError(t, err)
+	assert.Contains(t, pages, "feature-auth-api.html")
+	assert.Contains(t, pages, "feature-auth-api-2.html")
+	assert.Contains(t, string(pages["feature-auth.html"]), `<a href="feature-auth-api-2.html">Auth API</a>`, "links use the numbered slug")
+	assert.Contains(t, string(pages["feature-auth-api-2.html"]), `<a href="feature-auth.html">Auth</a>`)
+	assert.Contains(t, string(pages["feature-auth-api-2.html"]), "in the 90 days before")
+}
@@ -0,0 +1,333 @@
+package sqlite
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+)
+
+// SchemaVersion is the version of the exported schema, stored in the metadata table
+const SchemaVersion = "1"
+
+// Export lays out the analysis as normalized tables: metadata, authors, commits, files,
+// commit_files, features, feature_commits, ownership, bugs, bug_files and bug_origins.
+// Timestamps are RFC 3339 text in UTC, which SQLite's date functions understand
+func Export(url string, commits []git.CommitInfo, featureAnalysis map[string]*models.Feature) []Table {
+	e := &exporter{
+		authorIDs: make(map[string]int64),
+		commitIDs: make(map[string]int64),
+		fileIDs:   make(map[string]int64),
+	}
+
+	// Commits get their ids in history order, oldest first
+	for i := len(commits) - 1; i >= 0; i-- {
+		e.addCommit(commits[i])
+	}
+
+	names := make([]string, 0, len(featureAnalysis))
+	for name := range featureAnalysis {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	featureIDs := make(map[string]int64, len(names))
+	for i, name := range names {
+		featureIDs[name] = int64(i + 1)
+	}
+	for _, name := range names {
+		e.addFeature(featureAnalysis[name], featureIDs)
+	}
+
+	head := ""
+	if len(commits) > 0 {
+		head = commits[0].Commit.Hash.String()
+	}
+	metadata := [][]Value{
+		{"schema_version", SchemaVersion},
+		{"repository", url},
+		{"head", head},
+	}
+
+	return []Table{
+		{
+			Name:    "metadata",
+			Columns: []string{"key TEXT NOT NULL", "value TEXT NOT NULL"},
+			Rows:    metadata,
+			Indexes: []Index{{Name: "metadata_key", Columns: []string{"key"}, Unique: true}},
+		},
+		{
+			Name:    "authors",
+			Columns: []string{"id INTEGER PRIMARY KEY", "email TEXT NOT NULL", "name TEXT NOT NULL"},
+			Rows:    e.authors,
+			Indexes: []Index{{Name: "authors_email", Columns: []string{"email"}, Unique: true}},
+		},
+		{
+			Name: "commits",
+			Columns: []string{
+				"id INTEGER PRIMARY KEY",
+				"hash TEXT NOT NULL",
+				"author_id INTEGER NOT NULL REFERENCES authors (id)",
+				"authored_at TEXT NOT NULL",
+				"subject TEXT NOT NULL",
+				"message TEXT NOT NULL",
+				"parents INTEGER NOT NULL",
+			},
+			Rows: e.commits,
+			Indexes: []Index{
+				{Name: "commits_hash", Columns: []string{"hash"}, Unique: true},
+				{Name: "commits_author", Columns: []string{"author_id"}},
+				{Name: "commits_authored_at", Columns: []string{"authored_at"}},
+			},
+		},
+		{
+			Name:    "files",
+			Columns: []string{"id INTEGER PRIMARY KEY", "path TEXT NOT NULL"},
+			Rows:    e.files,
+			Indexes: []Index{{Name: "files_path", Columns: []string{"path"}, Unique: true}},
+		},
+		{
+			Name: "commit_files",
+			Columns: []string{
+				"commit_id INTEGER NOT NULL REFERENCES commits (id)",
+				"file_id INTEGER NOT NULL REFERENCES files (id)",
+			},
+			Rows: e.commitFiles,
+			Indexes: []Index{
+				{Name: "commit_files_commit", Columns: []string{"commit_id"}},
+				{Name: "commit_files_file", Columns: []string{"file_id"}},
+			},
+		},
+		{
+			Name: "features",
+			Columns: []string{
+				"id INTEGER PRIMARY KEY",
+				"name TEXT NOT NULL",
+				"parent_id INTEGER REFERENCES features (id)",
+				"created_at TEXT",
+				"last_updated TEXT",
+			},
+			Rows: e.features,
+			Indexes: []Index{
+				{Name: "features_name", Columns: []string{"name"}, Unique: true},
+				{Name: "features_parent", Columns: []string{"parent_id"}},
+			},
+		},
+		{
+			Name: "feature_commits",
+			Columns: []string{
+				"feature_id INTEGER NOT NULL REFERENCES features (id)",
+				"commit_id INTEGER NOT NULL REFERENCES commits (id)",
+				"detector TEXT",
+				"rule TEXT",
+				"pattern TEXT",
+				"matched TEXT",
+				"confidence REAL",
+			},
+			Rows: e.featureCommits,
+			Indexes: []Index{
+				{Name: "feature_commits_feature", Columns: []string{"feature_id"}},
+				{Name: "feature_commits_commit", Columns: []string{"commit_id"}},
+			},
+		},
+		{
+			Name: "ownership",
+			Columns: []string{
+				"feature_id INTEGER NOT NULL REFERENCES features (id)",
+				"author_id INTEGER NOT NULL REFERENCES authors (id)",
+				"role TEXT NOT NULL",
+				"share REAL NOT NULL",
+			},
+			Rows: e.ownership,
+			Indexes: []Index{
+				{Name: "ownership_feature", Columns: []string{"feature_id"}},
+				{Name: "ownership_author", Columns: []string{"author_id"}},
+			},
+		},
+		{
+			Name: "bugs",
+			Columns: []string{
+				"id INTEGER PRIMARY KEY",
+				"feature_id INTEGER NOT NULL REFERENCES features (id)",
+				"commit_id INTEGER REFERENCES commits (id)",
+				"author_id INTEGER REFERENCES authors (id)",
+				"description TEXT NOT NULL",
+				"fixed_at TEXT NOT NULL",
+				"reported_at TEXT",
+				"introduced_at TEXT",
+			},
+			Rows: e.bugs,
+			Indexes: []Index{
+				{Name: "bugs_feature", Columns: []string{"feature_id"}},
+				{Name: "bugs_commit", Columns: []string{"commit_id"}},
+			},
+		},
+		{
+			Name: "bug_files",
+			Columns: []string{
+				"bug_id INTEGER NOT NULL REFERENCES bugs (id)",
+				"file_id INTEGER NOT NULL REFERENCES files (id)",
+			},
+			Rows:    e.bugFiles,
+			Indexes: []Index{{Name: "bug_files_bug", Columns: []string{"bug_id"}}, {Name: "bug_files_file", Columns: []string{"file_id"}}},
+		},
+		{
+			Name: "bug_origins",
+			Columns: []string{
+				"bug_id INTEGER NOT NULL REFERENCES bugs (id)",
+				"commit_id INTEGER REFERENCES commits (id)",
+				"hash TEXT NOT NULL",
+			},
+			Rows:    e.bugOrigins,
+			Indexes: []Index{{Name: "bug_origins_bug", Columns: []string{"bug_id"}}, {Name: "bug_origins_commit", Columns: []string{"commit_id"}}},
+		},
+	}
+}
+
+type exporter struct {
+	authorIDs map[string]int64 // email -> id
+	commitIDs map[string]int64 // hash -> id
+	fileIDs   map[string]int64 // path -> id
+
+	authors, commits, files, commitFiles [][]Value
+	features, featureCommits, ownership  [][]Value
+	bugs, bugFiles, bugOrigins           [][]Value
+}
+
+func (e *exporter) addCommit(commit git.CommitInfo) {
+	hash := commit.Commit.Hash.String()
+	if _, ok := e.commitIDs[hash]; ok {
+		return
+	}
+	id := int64(len(e.commits) + 1)
+	e.commitIDs[hash] = id
+
+	author := commit.Commit.Author
+	message := strings.TrimSpace(commit.Commit.Message)
+	subject, _, _ := strings.Cut(message, "\n")
+	e.commits = append(e.commits, []Value{
+		id, hash, e.author(author.Email, author.Name), timestamp(author.When),
+		strings.TrimSpace(subject), message, len(commit.Commit.ParentHashes),
+	})
+
+	seen := make(map[string]bool, len(commit.Files))
+	for _, path := range commit.Files {
+		if !seen[path] {
+			seen[path] = true
+			e.commitFiles = append(e.commitFiles, []Value{id, e.file(path)})
+		}
+	}
+}
+
+func (e *exporter) addFeature(feature *models.Feature, featureIDs map[string]int64) {
+	id := featureIDs[feature.Name]
+	var parent Value
+	if parentID, ok := featureIDs[feature.Parent]; ok {
+		parent = parentID
+	}
+	var created, updated Value
+	if len(feature.Commits) > 0 {
+		created, updated = timestamp(feature.CreatedAt), timestamp(feature.LastUpdated)
+	}
+	e.features = append(e.features, []Value{id, feature.Name, parent, created, updated})
+
+	var assigned [][]Value
+	for _, commit := range feature.Commits {
+		hash := commit.Commit.Hash.String()
+		commitID, ok := e.commitIDs[hash]
+		if !ok {
+			continue
+		}
+		row := []Value{id, commitID, nil, nil, nil, nil, nil}
+		if assignment, ok := feature.Assignments[hash]; ok {
+			row = []Value{id, commitID, assignment.Detector, assignment.Rule, assignment.Pattern, assignment.Text, assignment.Confidence}
+		}
+		assigned = append(assigned, row)
+	}
+	sort.SliceStable(assigned, func(i, j int) bool {
+		return assigned[i][1].(int64) < assigned[j][1].(int64)
+	})
+	e.featureCommits = append(e.featureCommits, assigned...)
+
+	for _, role := range []struct {
+		name   string
+		owners map[string]float64
+	}{{"primary", feature.Owners}, {"backup", feature.BackupOwners}} {
+		emails := make([]string, 0, len(role.owners))
+		for email := range role.owners {
+			emails = append(emails, email)
+		}
+		sort.Strings(emails)
+		for _, email := range emails {
+			e.ownership = append(e.ownership, []Value{id, e.author(email, ""), role.name, role.owners[email]})
+		}
+	}
+
+	bugs := append([]models.Bug(nil), feature.Bugs...)
+	sort.SliceStable(bugs, func(i, j int) bool {
+		if !bugs[i].FixedAt.Equal(bugs[j].FixedAt) {
+			return bugs[i].FixedAt.Before(bugs[j].FixedAt)
+		}
+		return bugs[i].CommitHash < bugs[j].CommitHash
+	})
+	for _, bug := range bugs {
+		bugID := int64(len(e.bugs) + 1)
+		var author Value
+		if bug.AuthorEmail != "" {
+			author = e.author(bug.AuthorEmail, "")
+		}
+		e.bugs = append(e.bugs, []Value{
+			bugID, id, e.commitID(bug.CommitHash), author, strings.TrimSpace(bug.Description),
+			timestamp(bug.FixedAt), optionalTimestamp(bug.ReportedAt), optionalTimestamp(bug.IntroducedAt),
+		})
+		for _, path := range bug.AffectedFiles {
+			e.bugFiles = append(e.bugFiles, []Value{bugID, e.file(path)})
+		}
+		for _, origin := range bug.IntroducedBy {
+			e.bugOrigins = append(e.bugOrigins, []Value{bugID, e.commitID(origin.CommitHash), origin.CommitHash})
+		}
+	}
+}
+
+// author returns the id of an author, adding them when new; the latest name wins
+func (e *exporter) author(email, name string) int64 {
+	id, ok := e.authorIDs[email]
+	if !ok {
+		id = int64(len(e.authors) + 1)
+		e.authorIDs[email] = id
+		e.authors = append(e.authors, []Value{id, email, name})
+	} else if name != "" {
+		e.authors[id-1][2] = name
+	}
+	return id
+}
+
+func (e *exporter) file(path string) int64 {
+	id, ok := e.fileIDs[path]
+	if !ok {
+		id = int64(len(e.files) + 1)
+		e.fileIDs[path] = id
+		e.files = append(e.files, []Value{id, path})
+	}
+	return id
+}
+
+// commitID returns the id of a commit in the history, or nil
+func (e *exporter) commitID(hash string) Value {
+	if id, ok := e.commitIDs[hash]; ok {
+		return id
+	}
+	return nil
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func optionalTimestamp(t time.Time) Value {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamp(t)
+}
@@ -0,0 +1,140 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-history-onboarding/internal/git"
+	"git-history-onboarding/internal/models"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestCommit(hash string, message string, name, email string, when time.Time, files []string) git.CommitInfo {
+	return git.CommitInfo{
+		Commit: &object.Commit{
+			Hash:    plumbing.NewHash(hash),
+			Author:  object.Signature{Name: name, Email: email, When: when},
+			Message: message,
+		},
+		Files: files,
+	}
+}
+
+func tables(t *testing.T) map[string]Table {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+	commits := []git.CommitInfo{
+		createTestCommit("cccc03", "fix(refunds): rounding\n\nDetails", "Jane D.", "jane@example.com", start.AddDate(0, 1, 0), []string{"pay/refund.go"}),
+		createTestCommit("bbbb02", "feat(refunds): partial refunds", "John", "john@example.com", start.AddDate(0, 0, 1), []string{"pay/refund.go", "pay/refund_test.go", "pay/refund.go"}),
+		createTestCommit("aaaa01", "feat(payments): charge", "Jane", "jane@example.com", start, []string{"pay/charge.go"}),
+	}
+	refunds := &models.Feature{
+		Name:         "Payments > Refunds",
+		Parent:       "Payments",
+		Commits:      commits[:2],
+		Owners:       map[string]float64{"john@example.com": 0.5, "jane@example.com": 0.5},
+		BackupOwners: map[string]float64{},
+		CreatedAt:    start.AddDate(0, 0, 1),
+		LastUpdated:  start.AddDate(0, 1, 0),
+		Assignments: map[string]models.Assignment{
+			commits[1].Commit.Hash.String(): {Detector: "patterns", Rule: "scope", Pattern: "refunds", Text: "refunds", Confidence: 1},
+		},
+		Bugs: []models.Bug{{
+			CommitHash:    commits[0].Commit.Hash.String(),
+			Description:   "fix(refunds): rounding ",
+			AuthorEmail:   "jane@example.com",
+			FixedAt:       start.AddDate(0, 1, 0),
+			AffectedFiles: []string{"pay/refund.go"},
+			IntroducedBy:  []models.BugOrigin{{CommitHash: commits[1].Commit.Hash.String()}, {CommitHash: "ffff99"}},
+		}},
+	}
+	featureAnalysis := map[string]*models.Feature{
+		"Payments > Refunds": refunds,
+		"Payments": {
+			Name:         "Payments",
+			Children:     []string{"Payments > Refunds"},
+			Commits:      commits,
+			Owners:       map[string]float64{"jane@example.com": 0.67},
+			BackupOwners: map[string]float64{"john@example.com": 0.33},
+			CreatedAt:    start,
+			LastUpdated:  start.AddDate(0, 1, 0),
+		},
+		"Admin": {Name: "Admin"},
+	}
+
+	byName := make(map[string]Table)
+	for _, table := range Export("https://github.com/acme/shop", commits, featureAnalysis) {
+		byName[table.Name] = table
+	}
+	return byName
+}
+
+func TestExport(t *testing.T) {
+	tables := tables(t)
+
+	assert.Equal(t, [][]Value{{int64(1), "jane@example.com", "Jane D."}, {int64(2), "john@example.com", "John"}},
+		tables["authors"].Rows, "the latest name wins")
+
+	commits := tables["commits"].Rows
+	if assert.Len(t, commits, 3) {
+		assert.Equal(t, []Value{int64(1), "aaaa010000000000000000000000000000000000", int64(1), "2024-01-15T09:00:00Z",
+			"feat(payments): charge", "feat(payments): charge", 0}, commits[0], "oldest first, in UTC")
+		assert.Equal(t, "fix(refunds): rounding", commits[2][4])
+		assert.Equal(t, "fix(refunds): rounding\n\nDetails", commits[2][5])
+	}
+	assert.Equal(t, [][]Value{{int64(1), int64(1)}, {int64(2), int64(2)}, {int64(2), int64(3)}, {int64(3), int64(2)}},
+		tables["commit_files"].Rows, "files are listed once per commit")
+	assert.Len(t, tables["files"].Rows, 3)
+
+	assert.Equal(t, [][]Value{
+		{int64(1), "Admin", nil, nil, nil},
+		{int64(2), "Payments", nil, "2024-01-15T09:00:00Z", "2024-02-15T09:00:00Z"},
+		{int64(3), "Payments > Refunds", int64(2), "2024-01-16T09:00:00Z", "2024-02-15T09:00:00Z"},
+	}, tables["features"].Rows)
+
+	assert.Equal(t, [][]Value{
+		{int64(3), int64(2), "patterns", "scope", "refunds", "refunds", 1.0},
+		{int64(3), int64(3), nil, nil, nil, nil, nil},
+	}, tables["feature_commits"].Rows[3:], "commits without an assignment have no reason")
+
+	assert.Equal(t, [][]Value{
+		{int64(2), int64(1), "primary", 0.67},
+		{int64(2), int64(2), "backup", 0.33},
+		{int64(3), int64(1), "primary", 0.5},
+		{int64(3), int64(2), "primary", 0.5},
+	}, tables["ownership"].Rows)
+
+	assert.Equal(t, [][]Value{
+		{int64(1), int64(3), int64(3), int64(1), "fix(refunds): rounding", "2024-02-15T09:00:00Z", nil, nil},
+	}, tables["bugs"].Rows)
+	assert.Equal(t, [][]Value{{int64(1), int64(2)}}, tables["bug_files"].Rows)
+	assert.Equal(t, [][]Value{
+		{int64(1), int64(2), "bbbb020000000000000000000000000000000000"},
+		{int64(1), nil, "ffff99"},
+	}, tables["bug_origins"].Rows, "origins outside the history have no commit id")
+
+	assert.Equal(t, []Value{"schema_version", SchemaVersion}, tables["metadata"].Rows[0])
+}
+
+func TestExportWrites(t *testing.T) {
+	var all []Table
+	for _, table := range tables(t) {
+		all = append(all, table)
+	}
+	path := filepath.Join(t.TempDir(), "export.db")
+	if !assert.NoError(t, WriteFile(path, all), "the rows match the columns and unique indexes") {
+		return
+	}
+
+	assert.Equal(t, "ok\n", query(t, path, "PRAGMA integrity_check"))
+	assert.Equal(t, "", query(t, path, "PRAGMA foreign_key_check"))
+	assert.Equal(t, "Payments|jane@example.com|primary|0.67\nPayments|john@example.com|backup|0.33\n"+
+		"Payments > Refunds|jane@example.com|primary|0.5\nPayments > Refunds|john@example.com|primary|0.5\n",
+		query(t, path, `SELECT f.name, a.email, o.role, o.share FROM ownership o
+			JOIN features f ON f.id = o.feature_id JOIN authors a ON a.id = o.author_id ORDER BY f.name, a.email`))
+	assert.Equal(t, "pay/refund.go|2\npay/charge.go|1\npay/refund_test.go|1\n",
+		query(t, path, `SELECT path, count(*) FROM commit_files JOIN files ON files.id = file_id
+			GROUP BY path ORDER BY count(*) DESC, path`))
+}
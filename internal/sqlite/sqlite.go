@@ -0,0 +1,126 @@
+// Package sqlite writes analysis results to SQLite database files through the pure-Go
+// modernc.org/sqlite driver, so that neither cgo nor a system SQLite library is needed
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+// Value is a column value: nil, int, int64, float64, bool (stored as 0 or 1) or string
+type Value interface{}
+
+// Table is a table and its rows, in insertion order
+type Table struct {
+	Name    string
+	Columns []string // definitions, e.g. "id INTEGER PRIMARY KEY" or "email TEXT NOT NULL"
+	Rows    [][]Value
+	Indexes []Index
+}
+
+// Index is an index over columns of its table
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// SQL returns the CREATE TABLE statement of the table
+func (t Table) SQL() string {
+	return fmt.Sprintf("CREATE TABLE %s (%s)", t.Name, strings.Join(t.Columns, ", "))
+}
+
+// SQL returns the CREATE INDEX statement of the index on table
+func (i Index) SQL(table string) string {
+	unique := ""
+	if i.Unique {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)", unique, i.Name, table, strings.Join(i.Columns, ", "))
+}
+
+// insertSQL returns the INSERT statement of a row of the table
+func (t Table) insertSQL() string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(t.Columns)), ", ")
+	return fmt.Sprintf("INSERT INTO %s VALUES (%s)", t.Name, placeholders)
+}
+
+// Open opens a database file with the driver the package writes with
+func Open(path string) (*sql.DB, error) {
+	return sql.Open("sqlite", path)
+}
+
+// WriteFile writes a database with the tables to path, replacing any existing file.
+// Nothing is left at path when writing fails
+func WriteFile(path string, tables []Table) (err error) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	db, err := Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := db.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := create(tx, tables); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// create creates and fills the tables, then indexes them
+func create(tx *sql.Tx, tables []Table) error {
+	for _, table := range tables {
+		if _, err := tx.Exec(table.SQL()); err != nil {
+			return fmt.Errorf("create table %s: %w", table.Name, err)
+		}
+		if err := insert(tx, table); err != nil {
+			return err
+		}
+		for _, index := range table.Indexes {
+			if _, err := tx.Exec(index.SQL(table.Name)); err != nil {
+				return fmt.Errorf("create index %s: %w", index.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func insert(tx *sql.Tx, table Table) error {
+	stmt, err := tx.Prepare(table.insertSQL())
+	if err != nil {
+		return fmt.Errorf("prepare %s rows: %w", table.Name, err)
+	}
+	defer stmt.Close()
+
+	for i, row := range table.Rows {
+		if len(row) != len(table.Columns) {
+			return fmt.Errorf("row %d of %s has %d values for %d columns", i+1, table.Name, len(row), len(table.Columns))
+		}
+		args := make([]interface{}, len(row))
+		for j, value := range row {
+			args[j] = value
+		}
+		if _, err := stmt.Exec(args...); err != nil {
+			return fmt.Errorf("insert row %d of %s: %w", i+1, table.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// query runs a query on a database file and returns its rows, one per line with "|"
+// between columns and NULL as an empty string
+func query(t *testing.T, path string, q string) string {
+	t.Helper()
+	db, err := Open(path)
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	defer db.Close()
+
+	rows, err := db.Query(q)
+	if !assert.NoError(t, err, q) {
+		return ""
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	assert.NoError(t, err)
+
+	var b strings.Builder
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if !assert.NoError(t, rows.Scan(pointers...)) {
+			return ""
+		}
+		for i, value := range values {
+			if i > 0 {
+				b.WriteString("|")
+			}
+			if value != nil {
+				fmt.Fprint(&b, value)
+			}
+		}
+		b.WriteString("\n")
+	}
+	assert.NoError(t, rows.Err())
+	return b.String()
+}
+
+func usersTable() Table {
+	var rows [][]Value
+	for i := 1; i <= 3000; i++ {
+		rows = append(rows, []Value{i * 10, fmt.Sprintf("user%04d", i), strings.Repeat("x", i%5000)})
+	}
+	return Table{
+		Name:    "users",
+		Columns: []string{"id INTEGER PRIMARY KEY", "name TEXT NOT NULL", "bio TEXT"},
+		Rows:    rows,
+		Indexes: []Index{{Name: "users_name", Columns: []string{"name"}, Unique: true}},
+	}
+}
+
+func TestSQL(t *testing.T) {
+	table := usersTable()
+	assert.Equal(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, bio TEXT)", table.SQL())
+	assert.Equal(t, "CREATE UNIQUE INDEX users_name ON users (name)", table.Indexes[0].SQL("users"))
+	assert.Equal(t, "INSERT INTO users VALUES (?, ?, ?)", table.insertSQL())
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	values := Table{
+		Name:    "t",
+		Columns: []string{"i INTEGER", "f REAL", "b INTEGER", "s TEXT"},
+		Rows: [][]Value{
+			{int64(math.MinInt64), -1.5, true, ""},
+			{-129, 0.1, false, "zoë"},
+			{int64(1) << 40, math.MaxFloat64, nil, nil},
+		},
+		Indexes: []Index{{Name: "t_f", Columns: []string{"f"}}, {Name: "t_s_i", Columns: []string{"s", "i"}}},
+	}
+	assert.NoError(t, os.WriteFile(path, []byte("not a database"), 0644))
+	if !assert.NoError(t, WriteFile(path, []Table{usersTable(), values}), "an existing file is replaced") {
+		return
+	}
+
+	assert.Equal(t, "ok\n", query(t, path, "PRAGMA integrity_check"))
+	assert.Equal(t, "table|users\nindex|users_name\ntable|t\nindex|t_f\nindex|t_s_i\n",
+		query(t, path, "SELECT type, name FROM sqlite_master ORDER BY rowid"))
+	assert.Equal(t, "3000|10|30000|4501500\n", query(t, path, "SELECT count(*), min(id), max(id), sum(length(bio)) FROM users"))
+	assert.Equal(t, "29990|2999\n", query(t, path, "SELECT id, length(bio) FROM users INDEXED BY users_name WHERE name = 'user2999'"))
+	assert.Equal(t, "-9223372036854775808|-1.5|1|\n-129|0.1|0|zoë\n1099511627776|1.7976931348623157e+308||\n",
+		query(t, path, "SELECT i, f, b, s FROM t INDEXED BY t_f ORDER BY f"), "bools are stored as 0 and 1")
+}
+
+func TestWriteFileErrors(t *testing.T) {
+	table := func(rows ...[]Value) Table {
+		return Table{
+			Name:    "t",
+			Columns: []string{"id INTEGER PRIMARY KEY", "v TEXT"},
+			Rows:    rows,
+			Indexes: []Index{{Name: "t_v", Columns: []string{"v"}, Unique: true}},
+		}
+	}
+	path := filepath.Join(t.TempDir(), "test.db")
+	assert.ErrorContains(t, WriteFile(path, []Table{table([]Value{1})}), "row 1 of t has 1 values for 2 columns")
+	assert.ErrorContains(t, WriteFile(path, []Table{table([]Value{1, "a"}, []Value{1, "b"})}), "insert row 2 of t")
+	assert.ErrorContains(t, WriteFile(path, []Table{table([]Value{1, "a"}, []Value{2, "a"})}), "create index t_v")
+
+	missing := table([]Value{1, "a"})
+	missing.Indexes[0].Columns = []string{"w"}
+	assert.ErrorContains(t, WriteFile(path, []Table{missing}), "create index t_v")
+
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "failed writes leave no file behind")
+}